@@ -0,0 +1,254 @@
+package liberlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// BodyDecoder decodes an HTTP body to the value logged, which goes through the redaction rules. The
+// params are the ones of the Content-Type, as the boundary of multipart/form-data.
+type BodyDecoder func(body []byte, params map[string]string) (interface{}, error)
+
+var bodyDecoders = struct {
+	sync.RWMutex
+	byType map[string]BodyDecoder
+}{
+	byType: map[string]BodyDecoder{
+		"application/json":                  decodeJSON,
+		"application/x-www-form-urlencoded": decodeForm,
+		"multipart/form-data":               decodeMultipart,
+		"application/xml":                   decodeXML,
+		"text/xml":                          decodeXML,
+		"application/soap+xml":              decodeXML,
+		"text/plain":                        decodeText,
+	},
+}
+
+// RegisterBodyDecoder sets the decoder of the bodies of the media type, as application/json.
+func RegisterBodyDecoder(mediaType string, decoder BodyDecoder) {
+	bodyDecoders.Lock()
+	defer bodyDecoders.Unlock()
+
+	bodyDecoders.byType[strings.ToLower(mediaType)] = decoder
+}
+
+func bodyDecoder(mediaType string) (BodyDecoder, bool) {
+	bodyDecoders.RLock()
+	defer bodyDecoders.RUnlock()
+
+	decoder, ok := bodyDecoders.byType[mediaType]
+
+	return decoder, ok
+}
+
+// decodeBody decodes the body by its Content-Type. The bodies without one are decoded as JSON, the
+// suffixes +json and +xml as JSON and XML, the other text types as plain text and the remaining types
+// are logged as their content type and size only.
+func decodeBody(contentType string, body []byte) (interface{}, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
+	}
+
+	if contentType == "" {
+		return decodeJSON(body, nil)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	if decoder, ok := bodyDecoder(mediaType); ok {
+		return decoder(body, params)
+	}
+
+	switch {
+	case strings.HasSuffix(mediaType, "+json"):
+		return decodeJSON(body, params)
+	case strings.HasSuffix(mediaType, "+xml"):
+		return decodeXML(body, params)
+	case strings.HasPrefix(mediaType, "text/"):
+		return decodeText(body, params)
+	}
+
+	return map[string]interface{}{"content_type": mediaType, "size": len(body)}, nil
+}
+
+// decodeJSON keeps the JSON raw, to be redacted by RedactJSON.
+func decodeJSON(body []byte, params map[string]string) (interface{}, error) {
+	var raw json.RawMessage
+
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	return raw, nil
+}
+
+func decodeText(body []byte, params map[string]string) (interface{}, error) {
+	return string(body), nil
+}
+
+func decodeForm(body []byte, params map[string]string) (interface{}, error) {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	return formValues(values), nil
+}
+
+// decodeMultipart logs the values of the fields and only the name, content type and size of the files.
+func decodeMultipart(body []byte, params map[string]string) (interface{}, error) {
+	boundary := params["boundary"]
+	if boundary == "" {
+		return nil, errors.New("multipart body without boundary")
+	}
+
+	fields := url.Values{}
+	files := map[string][]interface{}{}
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if part.FileName() == "" {
+			value, err := io.ReadAll(part)
+			if err != nil {
+				return nil, err
+			}
+
+			fields.Add(part.FormName(), string(value))
+			continue
+		}
+
+		size, err := io.Copy(io.Discard, part)
+		if err != nil {
+			return nil, err
+		}
+
+		files[part.FormName()] = append(files[part.FormName()], map[string]interface{}{
+			"filename":     part.FileName(),
+			"content_type": part.Header.Get("Content-Type"),
+			"size":         size,
+		})
+	}
+
+	decoded := formValues(fields)
+
+	for name, metadata := range files {
+		if len(metadata) == 1 {
+			decoded[name] = metadata[0]
+			continue
+		}
+
+		decoded[name] = metadata
+	}
+
+	return decoded, nil
+}
+
+func formValues(values url.Values) map[string]interface{} {
+	decoded := map[string]interface{}{}
+
+	for key, value := range values {
+		if len(value) == 1 {
+			decoded[key] = value[0]
+			continue
+		}
+
+		decoded[key] = value
+	}
+
+	return decoded
+}
+
+// decodeXML decodes the XML, as the SOAP envelopes, to maps keyed by the local names of the elements, so
+// the redaction keys select them. The attributes are keyed by @ and their name, the text of the elements
+// with children by #text, and the repeated elements become arrays.
+func decodeXML(body []byte, params map[string]string) (interface{}, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			value, err := decodeXMLElement(decoder, start)
+			if err != nil {
+				return nil, err
+			}
+
+			return map[string]interface{}{start.Name.Local: value}, nil
+		}
+	}
+}
+
+func decodeXMLElement(decoder *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	element := map[string]interface{}{}
+	text := strings.Builder{}
+
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+
+		element["@"+attr.Name.Local] = attr.Value
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			child, err := decodeXMLElement(decoder, token)
+			if err != nil {
+				return nil, err
+			}
+
+			name := token.Name.Local
+
+			switch existing := element[name].(type) {
+			case nil:
+				element[name] = child
+			case []interface{}:
+				element[name] = append(existing, child)
+			default:
+				element[name] = []interface{}{existing, child}
+			}
+		case xml.CharData:
+			text.Write(token)
+		case xml.EndElement:
+			content := strings.TrimSpace(text.String())
+
+			if len(element) == 0 {
+				return content, nil
+			}
+
+			if content != "" {
+				element["#text"] = content
+			}
+
+			return element, nil
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package liberlogger
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodeBody(t *testing.T) {
+	multipartBody := "--b\r\n" +
+		"Content-Disposition: form-data; name=\"document\"\r\n\r\n" +
+		"52998224725\r\n" +
+		"--b\r\n" +
+		"Content-Disposition: form-data; name=\"file\"; filename=\"contract.pdf\"\r\n" +
+		"Content-Type: application/pdf\r\n\r\n" +
+		"%PDF-1.4 secret\r\n" +
+		"--b--\r\n"
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{
+			name:        "Should decode a JSON",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"password": "123", "document": "52998224725"}`,
+			want:        `{"password": "REDACTED", "document": "5299****725"}`,
+		},
+		{
+			name: "Should decode a body without content type as JSON",
+			body: `{"password": "123"}`,
+			want: `{"password": "REDACTED"}`,
+		},
+		{
+			name:        "Should decode a problem+json",
+			contentType: "application/problem+json",
+			body:        `{"password": "123"}`,
+			want:        `{"password": "REDACTED"}`,
+		},
+		{
+			name:        "Should decode a form",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "grant_type=client_credentials&client_secret=abc&scope=a&scope=b",
+			want:        `{"grant_type": "client_credentials", "client_secret": "REDACTED", "scope": ["a", "b"]}`,
+		},
+		{
+			name:        "Should decode a multipart without the file bytes",
+			contentType: `multipart/form-data; boundary=b`,
+			body:        multipartBody,
+			want:        `{"document": "5299****725", "file": {"filename": "contract.pdf", "content_type": "application/pdf", "size": 15}}`,
+		},
+		{
+			name:        "Should decode a SOAP envelope",
+			contentType: "text/xml; charset=utf-8",
+			body: `<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <Login lang="pt"><User>joao</User><Password>123</Password></Login>
+    <Item>1</Item><Item>2</Item>
+  </soap:Body>
+</soap:Envelope>`,
+			want: `{"Envelope": {"Body": {"Login": {"@lang": "pt", "User": "joao", "Password": "REDACTED"}, "Item": ["1", "2"]}}}`,
+		},
+		{
+			name:        "Should decode a plain text",
+			contentType: "text/plain",
+			body:        "password is 123",
+			want:        `{"plain/text-type": "password is 123"}`,
+		},
+		{
+			name:        "Should log only the size of a binary",
+			contentType: "image/png",
+			body:        "\x89PNG",
+			want:        `{"content_type": "image/png", "size": 4}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := decodeBody(tt.contentType, []byte(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, _ := json.Marshal(Redact([]string{"password", "client_secret"}, []string{"document"}, body))
+
+			if !jsonEqual(t, got, []byte(tt.want)) {
+				t.Errorf("decodeBody() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterBodyDecoder(t *testing.T) {
+	RegisterBodyDecoder("application/vnd.liber", func(body []byte, params map[string]string) (interface{}, error) {
+		return map[string]interface{}{"custom": string(body)}, nil
+	})
+
+	body, err := decodeBody("application/vnd.liber", []byte("value"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if body.(map[string]interface{})["custom"] != "value" {
+		t.Errorf("decodeBody() = %v, want the custom decoder", body)
+	}
+}
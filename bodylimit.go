@@ -0,0 +1,100 @@
+package liberlogger
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultMaxBodyBytes is the default limit of the logged bytes of each body.
+const DefaultMaxBodyBytes = 64 << 10
+
+var (
+	maxRequestBodyBytes  atomic.Int64
+	maxResponseBodyBytes atomic.Int64
+)
+
+func init() {
+	SetMaxBodyBytes(DefaultMaxBodyBytes, DefaultMaxBodyBytes)
+}
+
+// SetMaxBodyBytes sets the limit of the logged bytes of the request and response bodies, of the
+// middlewares and the HttpClient. The bodies past it are logged truncated, with their size, and still
+// reach the handler or the client whole. A negative limit logs the bodies whole.
+func SetMaxBodyBytes(request int, response int) {
+	maxRequestBodyBytes.Store(int64(request))
+	maxResponseBodyBytes.Store(int64(response))
+}
+
+// bodyLimit returns the limit, or the default one when zero.
+func bodyLimit(limit int, defaultLimit *atomic.Int64) int64 {
+	if limit != 0 {
+		return int64(limit)
+	}
+
+	return defaultLimit.Load()
+}
+
+// truncatedBody is the start of a body larger than the limit, logged with the size of the whole body.
+type truncatedBody struct {
+	contentType string
+	data        []byte
+	size        int64 // -1 when unknown.
+}
+
+// readBody reads the body up to the limit, returning a body with every byte to put in its place. When the
+// read fails, the body returned still has the bytes read and the rest of the original, which returns the
+// error again to the handler or the client.
+func readBody(body io.ReadCloser, limit int64) ([]byte, io.ReadCloser, bool, error) {
+	reader := io.Reader(body)
+	if limit >= 0 {
+		reader = io.LimitReader(body, limit+1)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, restoredBody(data, body), false, err
+	}
+
+	if limit < 0 || int64(len(data)) <= limit {
+		body.Close()
+		return data, io.NopCloser(bytes.NewReader(data)), false, nil
+	}
+
+	return data[:limit], restoredBody(data, body), true, nil
+}
+
+// restoredBody reads the bytes already read and then the rest of the body, closing the body.
+func restoredBody(data []byte, body io.ReadCloser) io.ReadCloser {
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), body), body}
+}
+
+// redactTruncated redacts the start of a body. The JSON is redacted up to its last complete value, the
+// forms and texts as usual, and the other types are not logged.
+func (r *Redactor) redactTruncated(body truncatedBody) interface{} {
+	redacted := map[string]interface{}{"truncated": true}
+
+	if body.size >= 0 {
+		redacted["size"] = body.size
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(body.contentType)
+
+	switch {
+	case mediaType == "" || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		redacted["body"] = r.redactJSONPrefix(body.data)
+	case mediaType == "application/x-www-form-urlencoded":
+		if form, err := decodeForm(body.data, params); err == nil {
+			redacted["body"] = r.Redact(form)
+		}
+	case strings.HasPrefix(mediaType, "text/") && !strings.HasSuffix(mediaType, "xml"):
+		redacted["body"] = r.Redact(string(body.data))
+	}
+
+	return redacted
+}
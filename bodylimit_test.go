@@ -0,0 +1,270 @@
+package liberlogger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadBody(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         int64
+		wantData      string
+		wantTruncated bool
+	}{
+		{name: "Should read the whole body under the limit", limit: 20, wantData: "0123456789"},
+		{name: "Should read the whole body at the limit", limit: 10, wantData: "0123456789"},
+		{name: "Should truncate the body past the limit", limit: 4, wantData: "0123", wantTruncated: true},
+		{name: "Should read the whole body without limit", limit: -1, wantData: "0123456789"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, body, truncated, err := readBody(io.NopCloser(strings.NewReader("0123456789")), tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(data) != tt.wantData || truncated != tt.wantTruncated {
+				t.Errorf("readBody() = %q, %v, want %q, %v", data, truncated, tt.wantData, tt.wantTruncated)
+			}
+
+			if whole, _ := io.ReadAll(body); string(whole) != "0123456789" {
+				t.Errorf("readBody() body = %q, want every byte", whole)
+			}
+		})
+	}
+}
+
+func TestRedactor_redactTruncated(t *testing.T) {
+	redactor := keysRedactor([]string{"password"}, nil)
+
+	tests := []struct {
+		name string
+		body truncatedBody
+		want map[string]interface{}
+	}{
+		{
+			name: "Should redact the complete values of a JSON",
+			body: truncatedBody{contentType: "application/json", data: []byte(`{"password": "123", "items": [{"id": 1}, {"id": 2, "na`), size: 100},
+			want: map[string]interface{}{"truncated": true, "size": int64(100), "body": `{"password":"REDACTED","items":[{"id":1},{"id":2`},
+		},
+		{
+			name: "Should redact a form",
+			body: truncatedBody{contentType: "application/x-www-form-urlencoded", data: []byte("password=123&user=jo"), size: -1},
+			want: map[string]interface{}{"truncated": true, "body": map[string]interface{}{"password": REDACTED, "user": "jo"}},
+		},
+		{
+			name: "Should not log a binary",
+			body: truncatedBody{contentType: "application/pdf", data: []byte("%PDF"), size: 100},
+			want: map[string]interface{}{"truncated": true, "size": int64(100)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactor.Redact(tt.body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Redact() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBodyLimits(t *testing.T) {
+	SetMaxBodyBytes(10, 10)
+	defer SetMaxBodyBytes(DefaultMaxBodyBytes, DefaultMaxBodyBytes)
+
+	var out bytes.Buffer
+
+	largeBody := `{"id": 1, "description": "` + strings.Repeat("a", 100) + `"}`
+
+	handler := GorillaMuxRedacted([]string{"password"}, []string{}, []string{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != largeBody {
+			t.Errorf("handler body = %q, want the whole body", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(largeBody))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/files", strings.NewReader(largeBody))
+	req = req.WithContext(New(WithOutput(&out)).WithContext(context.Background()))
+	recorder := httptest.NewRecorder()
+
+	handler.ServeHTTP(recorder, req)
+
+	if recorder.Body.String() != largeBody {
+		t.Errorf("response = %q, want the whole body", recorder.Body.String())
+	}
+
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var log map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &log); err != nil {
+			t.Fatalf("invalid log %q: %v", scanner.Text(), err)
+		}
+
+		want := map[string]interface{}{"truncated": true, "size": float64(len(largeBody)), "body": `{"id":1`}
+		if !reflect.DeepEqual(log["body"], want) {
+			t.Errorf("body = %v, want %v", log["body"], want)
+		}
+	}
+}
+
+func TestBodyLimits_undecodable(t *testing.T) {
+	var out bytes.Buffer
+
+	largeBody := strings.Repeat("a", 1<<20)
+
+	client := HttpClient{
+		MaxResponseBodyBytes: 100,
+		Proxied: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Encoding": []string{"gzip"}},
+				Body:       io.NopCloser(strings.NewReader(largeBody)),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	req, _ := http.NewRequestWithContext(New(WithOutput(&out)).WithContext(context.Background()), http.MethodGet, "https://partner.com/files", nil)
+
+	res, err := client.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if data, _ := io.ReadAll(res.Body); string(data) != largeBody {
+		t.Errorf("response body has %d bytes, want the whole %d", len(data), len(largeBody))
+	}
+
+	scanner := bufio.NewScanner(&out)
+	scanner.Buffer(nil, 2<<20)
+
+	for scanner.Scan() {
+		if len(scanner.Bytes()) > 1024 {
+			t.Fatalf("log has %d bytes, want the body limited to 100", len(scanner.Bytes()))
+		}
+
+		var log map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &log); err != nil {
+			t.Fatalf("invalid log %q: %v", scanner.Text(), err)
+		}
+
+		if body, ok := log["body"].(map[string]interface{}); ok && body["plain/text-type"] != strings.Repeat("a", 100) {
+			t.Errorf("body = %v, want the first 100 bytes", body)
+		}
+	}
+}
+
+// failingBody returns the data and then fails, recording whether it was closed.
+type failingBody struct {
+	data   *strings.Reader
+	closed bool
+}
+
+var errBodyRead = errors.New("connection reset")
+
+func newFailingBody(data string) *failingBody {
+	return &failingBody{data: strings.NewReader(data)}
+}
+
+func (b *failingBody) Read(p []byte) (int, error) {
+	if b.data.Len() > 0 {
+		return b.data.Read(p)
+	}
+
+	return 0, errBodyRead
+}
+
+func (b *failingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestReadBodyError(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int64
+	}{
+		{name: "Should restore the body of a failed read with limit", limit: 20},
+		{name: "Should restore the body of a failed read without limit", limit: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := newFailingBody("0123")
+
+			_, body, _, err := readBody(original, tt.limit)
+			if !errors.Is(err, errBodyRead) {
+				t.Fatalf("readBody() error = %v, want %v", err, errBodyRead)
+			}
+
+			if body == nil {
+				t.Fatal("readBody() body = nil, want the restored body")
+			}
+
+			if data, err := io.ReadAll(body); string(data) != "0123" || !errors.Is(err, errBodyRead) {
+				t.Errorf("restored body = %q, %v, want %q, %v", data, err, "0123", errBodyRead)
+			}
+
+			if body.Close(); !original.closed {
+				t.Error("Close() did not close the original body")
+			}
+		})
+	}
+}
+
+func TestBodyReadErrors(t *testing.T) {
+	t.Run("Should give the handler the request body error", func(t *testing.T) {
+		var handlerErr error
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, handlerErr = io.ReadAll(r.Body)
+			r.Body.Close()
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/users", nil)
+		req.Body = newFailingBody(`{"name":`)
+
+		withLogger(New(WithOutput(io.Discard)), HTTPMiddleware()(handler)).ServeHTTP(httptest.NewRecorder(), req)
+
+		if !errors.Is(handlerErr, errBodyRead) {
+			t.Errorf("handler error = %v, want %v", handlerErr, errBodyRead)
+		}
+	})
+
+	t.Run("Should give the client the response body error", func(t *testing.T) {
+		client := HttpClient{
+			Proxied: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{},
+					Body:       newFailingBody(`{"id":`),
+					Request:    req,
+				}, nil
+			}),
+		}
+
+		req, _ := http.NewRequestWithContext(New(WithOutput(io.Discard)).WithContext(context.Background()), http.MethodGet, "https://partner.com/users", nil)
+
+		res, err := client.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+
+		if data, err := io.ReadAll(res.Body); string(data) != `{"id":` || !errors.Is(err, errBodyRead) {
+			t.Errorf("response body = %q, %v, want %q, %v", data, err, `{"id":`, errBodyRead)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package liberlogger
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// BypassPolicy tells when the redact rules are bypassed, logging the values they would replace by REDACTED.
+// The mask and tokenize rules always apply.
+type BypassPolicy string
+
+const (
+	BypassNever BypassPolicy = "never" // The default.
+	BypassDebug BypassPolicy = "debug" // Bypassed while the Default logger level is debug or trace.
+	BypassEnv   BypassPolicy = "env"   // Bypassed while the level is debug or trace, only in the allowed envs.
+)
+
+// DefaultAlwaysRedactKeys are redacted even when the redaction is bypassed.
+var DefaultAlwaysRedactKeys = []string{"password", "client_secret", "authorization"}
+
+// RedactBypass configures the bypass of the redact rules, see SetRedactBypass.
+type RedactBypass struct {
+	Policy           BypassPolicy
+	Env              string   // The env of the service, checked by BypassEnv.
+	AllowedEnvs      []string // The envs allowed by BypassEnv. Defaults to local.
+	AlwaysRedactKeys []string // Keys or selectors redacted even when bypassed. Defaults to DefaultAlwaysRedactKeys.
+}
+
+type redactBypassState struct {
+	enabled bool
+	always  *Redactor
+}
+
+var redactBypass atomic.Pointer[redactBypassState]
+
+func init() {
+	SetRedactBypass(RedactBypass{})
+}
+
+// SetRedactBypass sets the policy bypassing the redact rules, logging a warning with the Default logger
+// when it enables the bypass.
+func SetRedactBypass(bypass RedactBypass) {
+	allowedEnvs := bypass.AllowedEnvs
+	if len(allowedEnvs) == 0 {
+		allowedEnvs = []string{"local"}
+	}
+
+	alwaysRedactKeys := bypass.AlwaysRedactKeys
+	if alwaysRedactKeys == nil {
+		alwaysRedactKeys = DefaultAlwaysRedactKeys
+	}
+
+	enabled := false
+
+	switch BypassPolicy(strings.ToLower(string(bypass.Policy))) {
+	case BypassDebug:
+		enabled = true
+	case BypassEnv:
+		for _, env := range allowedEnvs {
+			if bypass.Env != "" && strings.EqualFold(env, bypass.Env) {
+				enabled = true
+			}
+		}
+	}
+
+	redactBypass.Store(&redactBypassState{
+		enabled: enabled,
+		always:  keysRedactor(alwaysRedactKeys, nil),
+	})
+
+	if enabled {
+		Default().Warn(context.Background()).
+			Str("policy", string(bypass.Policy)).
+			Str("env", bypass.Env).
+			Msg("liberlogger | Redaction bypass enabled, the REDACTED values are logged while the level is debug")
+	}
+}
+
+// ignoreRedacted tells if the redact rules are bypassed.
+func ignoreRedacted() bool {
+	if !redactBypass.Load().enabled {
+		return false
+	}
+
+	return Default().Level() <= zerolog.DebugLevel
+}
+
+// alwaysRedacted tells if the path is selected by the keys redacted even when bypassed.
+func alwaysRedacted(path []pathElem) bool {
+	_, ok := redactBypass.Load().always.match(path, false)
+	return ok
+}
@@ -0,0 +1,140 @@
+package liberlogger
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+func TestRedactBypass(t *testing.T) {
+	body := map[string]interface{}{"document": "52998224725", "password": "123456", "name": "Joao"}
+
+	tests := []struct {
+		name   string
+		bypass RedactBypass
+		level  string
+		want   interface{}
+	}{
+		{
+			name:   "Should not bypass by default",
+			bypass: RedactBypass{},
+			level:  "debug",
+			want:   map[string]interface{}{"document": REDACTED, "password": REDACTED, "name": "Joao"},
+		},
+		{
+			name:   "Should bypass in debug, except the keys always redacted",
+			bypass: RedactBypass{Policy: BypassDebug},
+			level:  "debug",
+			want:   map[string]interface{}{"document": "52998224725", "password": REDACTED, "name": "Joao"},
+		},
+		{
+			name:   "Should not bypass in info",
+			bypass: RedactBypass{Policy: BypassDebug},
+			level:  "info",
+			want:   map[string]interface{}{"document": REDACTED, "password": REDACTED, "name": "Joao"},
+		},
+		{
+			name:   "Should bypass in an allowed env",
+			bypass: RedactBypass{Policy: BypassEnv, Env: "local", AlwaysRedactKeys: []string{}},
+			level:  "debug",
+			want:   map[string]interface{}{"document": "52998224725", "password": "123456", "name": "Joao"},
+		},
+		{
+			name:   "Should not bypass in other envs",
+			bypass: RedactBypass{Policy: BypassEnv, Env: "production"},
+			level:  "debug",
+			want:   map[string]interface{}{"document": REDACTED, "password": REDACTED, "name": "Joao"},
+		},
+	}
+
+	previous := Default()
+	defer SetDefault(previous)
+	defer SetRedactBypass(RedactBypass{})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetDefault(New(WithLevel(tt.level)))
+			SetRedactBypass(tt.bypass)
+
+			if got := Redact([]string{"document", "password"}, []string{}, body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Redact() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactBypass_init(t *testing.T) {
+	saveGlobals(t)
+	SetDefault(newInitLogger())
+	log.Logger = zerolog.New(io.Discard)
+
+	body := map[string]interface{}{"document": "52998224725"}
+
+	tests := []struct {
+		name  string
+		level zerolog.Level
+		want  interface{}
+	}{
+		{name: "Should bypass in the debug global level", level: zerolog.DebugLevel, want: body},
+		{name: "Should bypass in the trace global level", level: zerolog.TraceLevel, want: body},
+		{name: "Should not bypass in the info global level", level: zerolog.InfoLevel, want: map[string]interface{}{"document": REDACTED}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Init("info")
+			zerolog.SetGlobalLevel(tt.level)
+			SetRedactBypass(RedactBypass{Policy: BypassDebug})
+
+			if got := Redact([]string{"document"}, []string{}, body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Redact() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetRedactBypass_warning(t *testing.T) {
+	saveGlobals(t)
+
+	out := &bytes.Buffer{}
+	SetDefault(New(WithOutput(out)))
+
+	SetRedactBypass(RedactBypass{Policy: BypassNever})
+
+	if out.Len() != 0 {
+		t.Errorf("SetRedactBypass() logs = %s, want no warning", out.String())
+	}
+
+	SetRedactBypass(RedactBypass{Policy: BypassDebug})
+
+	if !strings.Contains(out.String(), "Redaction bypass enabled") {
+		t.Errorf("SetRedactBypass() logs = %s, want the bypass warning", out.String())
+	}
+}
+
+func TestInitWithConfig_redactBypassWarning(t *testing.T) {
+	previous, previousLog := Default(), log.Logger
+	defer func() {
+		SetDefault(previous)
+		log.Logger = previousLog
+	}()
+	defer SetRedactBypass(RedactBypass{})
+
+	out := &bytes.Buffer{}
+	InitWithConfig(Config{Level: "debug", Writer: out, RedactBypass: "env", Env: "local"})
+
+	if !strings.Contains(out.String(), "Redaction bypass enabled") {
+		t.Errorf("InitWithConfig() logs = %s, want the bypass warning", out.String())
+	}
+
+	out.Reset()
+	InitWithConfig(Config{Level: "debug", Writer: out, RedactBypass: "env", Env: "production"})
+
+	if strings.Contains(out.String(), "Redaction bypass enabled") {
+		t.Errorf("InitWithConfig() logs = %s, want no bypass warning", out.String())
+	}
+}
@@ -0,0 +1,22 @@
+package liberlogger
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Chi logs the requests and responses of a chi router as HTTPMiddleware, with the route pattern matched
+// by chi as the route, as /users/{id}.
+func Chi(opts ...MiddlewareOption) func(next http.Handler) http.Handler {
+	return HTTPMiddleware(append([]MiddlewareOption{MiddlewareComponent(ComponentChi), MiddlewareRoute(chiRoute)}, opts...)...)
+}
+
+func chiRoute(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return ""
+	}
+
+	return rctx.RoutePattern()
+}
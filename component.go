@@ -0,0 +1,104 @@
+package liberlogger
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	ComponentChi        = "chi"
+	ComponentEcho       = "echo"
+	ComponentFiber      = "fiber"
+	ComponentGin        = "gin"
+	ComponentGorillaMux = "gorillamux"
+	ComponentGorm       = "gorm"
+	ComponentHTTP       = "http"
+	ComponentHttpClient = "httpclient"
+)
+
+// componentLevels holds the minimum level of each component, shared by a Logger and its children.
+type componentLevels struct {
+	mu     sync.RWMutex
+	levels map[string]zerolog.Level
+}
+
+func newComponentLevels() *componentLevels {
+	return &componentLevels{levels: map[string]zerolog.Level{}}
+}
+
+func (c *componentLevels) Get(component string) (zerolog.Level, bool) {
+	if component == "" {
+		return zerolog.NoLevel, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	level, ok := c.levels[component]
+
+	return level, ok
+}
+
+func (c *componentLevels) Set(component string, level zerolog.Level) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.levels[component] = level
+}
+
+func (c *componentLevels) SetAll(levels map[string]string) {
+	for component, level := range levels {
+		c.Set(component, parseLevel(level))
+	}
+}
+
+// Component returns a child of the Default logger tagging its logs with the component name, which
+// can have its own level.
+func Component(name string) *Logger {
+	return Default().Component(name)
+}
+
+// SetComponentLevel changes the minimum level of a component of the Default logger.
+func SetComponentLevel(name string, level string) {
+	Default().SetComponentLevel(name, level)
+}
+
+// Component returns a child Logger tagging its logs with the component name, which can have its own level.
+func (l *Logger) Component(name string) *Logger {
+	child := l.With(map[string]interface{}{"component": name})
+	child.component = name
+
+	return child
+}
+
+// SetComponentLevel changes the minimum level of a component, for the Logger and every child sharing it.
+func (l *Logger) SetComponentLevel(name string, level string) {
+	l.components.Set(name, parseLevel(level))
+}
+
+// WithComponentLevels sets the minimum level of each component, as component name to level.
+func WithComponentLevels(levels map[string]string) Option {
+	return func(l *Logger) {
+		l.components.SetAll(levels)
+	}
+}
+
+// ParseComponentLevels parses the component levels in the format "httpclient=debug,gorm=warn".
+func ParseComponentLevels(value string) map[string]string {
+	levels := map[string]string{}
+
+	for _, pair := range strings.Split(value, ",") {
+		component, level, found := strings.Cut(pair, "=")
+		component = strings.TrimSpace(component)
+
+		if !found || component == "" {
+			continue
+		}
+
+		levels[component] = strings.TrimSpace(level)
+	}
+
+	return levels
+}
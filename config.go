@@ -0,0 +1,103 @@
+package liberlogger
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// Config holds everything InitWithConfig needs to boot the logger, so every
+// service sets up its logs the same way.
+type Config struct {
+	Level           string                 // fatal, error, warn, info or debug. Defaults to info.
+	ComponentLevels map[string]string      // Minimum level of each component, as component name to level.
+	Writer          io.Writer              // Where the logs are written. Defaults to os.Stderr.
+	Format          string                 // FormatJSON (default) or FormatConsole.
+	TimeFormat      string                 // unix, unixms, unixmicro, unixnano, rfc3339, rfc3339nano or a time layout. Defaults to unix.
+	Service         string                 // Added to every log as "service".
+	Env             string                 // Added to every log as "env".
+	Version         string                 // Added to every log as "version".
+	Fields          map[string]interface{} // Static fields added to every log.
+	RedactKeys      []string               // Keys redacted by the Logger, as in Redact.
+	MaskKeys        []string               // Keys masked by the Logger, as in Redact.
+	TrustedProxies  []string               // IPs and CIDRs of the proxies trusted to set X-Forwarded-For.
+	TokenKey        string                 // Secret key of the ActionTokenize tokens, see SetTokenKey.
+	RedactBypass    string                 // BypassNever (default), BypassDebug or BypassEnv, see SetRedactBypass.
+	BypassEnvs      []string               // Envs where BypassEnv bypasses the redaction. Defaults to local.
+	AlwaysRedact    []string               // Keys redacted even when bypassed. Defaults to DefaultAlwaysRedactKeys.
+	MaxRequestBody  int                    // Limit of the logged bytes of the request bodies. Defaults to DefaultMaxBodyBytes, negative for none.
+	MaxResponseBody int                    // Limit of the logged bytes of the response bodies. Defaults to DefaultMaxBodyBytes, negative for none.
+	Caller          bool                   // Adds the file and line of the caller to every log.
+	DisableStack    bool                   // Stops marshaling the stack trace of errors.
+}
+
+// ConfigFromEnv builds a Config from the environment variables:
+//
+//	LOG_LEVEL, LOG_LEVELS (httpclient=debug,gorm=warn), LOG_OUTPUT (stdout or stderr), LOG_FORMAT, LOG_TIME_FORMAT,
+//	LOG_REDACT_KEYS, LOG_MASK_KEYS, LOG_TRUSTED_PROXIES (comma separated), LOG_TOKEN_KEY, LOG_REDACT_BYPASS,
+//	LOG_REDACT_BYPASS_ENVS, LOG_ALWAYS_REDACT_KEYS, LOG_MAX_REQUEST_BODY, LOG_MAX_RESPONSE_BODY (bytes), LOG_CALLER,
+//	LOG_DISABLE_STACK, DD_SERVICE, DD_ENV and DD_VERSION.
+func ConfigFromEnv() Config {
+	config := Config{
+		Level:           os.Getenv("LOG_LEVEL"),
+		ComponentLevels: ParseComponentLevels(os.Getenv("LOG_LEVELS")),
+		Format:          os.Getenv("LOG_FORMAT"),
+		TimeFormat:      os.Getenv("LOG_TIME_FORMAT"),
+		Service:         os.Getenv("DD_SERVICE"),
+		Env:             os.Getenv("DD_ENV"),
+		Version:         os.Getenv("DD_VERSION"),
+		RedactKeys:      envList("LOG_REDACT_KEYS"),
+		MaskKeys:        envList("LOG_MASK_KEYS"),
+		TrustedProxies:  envList("LOG_TRUSTED_PROXIES"),
+		TokenKey:        os.Getenv("LOG_TOKEN_KEY"),
+		RedactBypass:    os.Getenv("LOG_REDACT_BYPASS"),
+		BypassEnvs:      envList("LOG_REDACT_BYPASS_ENVS"),
+		AlwaysRedact:    envList("LOG_ALWAYS_REDACT_KEYS"),
+		MaxRequestBody:  envInt("LOG_MAX_REQUEST_BODY"),
+		MaxResponseBody: envInt("LOG_MAX_RESPONSE_BODY"),
+		Caller:          envBool("LOG_CALLER"),
+		DisableStack:    envBool("LOG_DISABLE_STACK"),
+	}
+
+	if strings.ToLower(os.Getenv("LOG_OUTPUT")) == "stdout" {
+		config.Writer = os.Stdout
+	}
+
+	return config
+}
+
+func envBool(key string) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return false
+	}
+
+	return value
+}
+
+func envInt(key string) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return 0
+	}
+
+	return value
+}
+
+func envList(key string) []string {
+	var list []string
+
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			list = append(list, value)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,297 @@
+package liberlogger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// saveGlobals restores the globals changed by Init and InitWithConfig at the end of the test.
+func saveGlobals(t *testing.T) {
+	t.Helper()
+
+	previous, previousLog, previousLevel := Default(), log.Logger, zerolog.GlobalLevel()
+	previousStack, previousTimeFormat := zerolog.ErrorStackMarshaler, zerolog.TimeFieldFormat
+
+	t.Cleanup(func() {
+		SetDefault(previous)
+		log.Logger = previousLog
+		zerolog.SetGlobalLevel(previousLevel)
+		zerolog.ErrorStackMarshaler = previousStack
+		zerolog.TimeFieldFormat = previousTimeFormat
+		SetMaxBodyBytes(DefaultMaxBodyBytes, DefaultMaxBodyBytes)
+		SetRedactBypass(RedactBypass{})
+		_ = SetTrustedProxies(nil)
+	})
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want Config
+	}{
+		{
+			name: "Should leave the defaults to InitWithConfig without variables",
+			want: Config{ComponentLevels: map[string]string{}},
+		},
+		{
+			name: "Should read every variable",
+			env: map[string]string{
+				"LOG_LEVEL":              "debug",
+				"LOG_LEVELS":             "httpclient=debug,gorm=warn",
+				"LOG_OUTPUT":             "stdout",
+				"LOG_FORMAT":             "console",
+				"LOG_TIME_FORMAT":        "rfc3339",
+				"LOG_REDACT_KEYS":        "password, token",
+				"LOG_MASK_KEYS":          "document",
+				"LOG_TRUSTED_PROXIES":    "10.0.0.0/8,127.0.0.1",
+				"LOG_TOKEN_KEY":          "secret",
+				"LOG_REDACT_BYPASS":      "env",
+				"LOG_REDACT_BYPASS_ENVS": "local,dev",
+				"LOG_ALWAYS_REDACT_KEYS": "password",
+				"LOG_MAX_REQUEST_BODY":   "1024",
+				"LOG_MAX_RESPONSE_BODY":  "-1",
+				"LOG_CALLER":             "true",
+				"LOG_DISABLE_STACK":      "1",
+				"DD_SERVICE":             "invoice-api",
+				"DD_ENV":                 "production",
+				"DD_VERSION":             "1.2.3",
+			},
+			want: Config{
+				Level:           "debug",
+				ComponentLevels: map[string]string{"httpclient": "debug", "gorm": "warn"},
+				Writer:          os.Stdout,
+				Format:          "console",
+				TimeFormat:      "rfc3339",
+				Service:         "invoice-api",
+				Env:             "production",
+				Version:         "1.2.3",
+				RedactKeys:      []string{"password", "token"},
+				MaskKeys:        []string{"document"},
+				TrustedProxies:  []string{"10.0.0.0/8", "127.0.0.1"},
+				TokenKey:        "secret",
+				RedactBypass:    "env",
+				BypassEnvs:      []string{"local", "dev"},
+				AlwaysRedact:    []string{"password"},
+				MaxRequestBody:  1024,
+				MaxResponseBody: -1,
+				Caller:          true,
+				DisableStack:    true,
+			},
+		},
+		{
+			name: "Should ignore the invalid numbers and booleans",
+			env: map[string]string{
+				"LOG_OUTPUT":           "stderr",
+				"LOG_MAX_REQUEST_BODY": "64k",
+				"LOG_CALLER":           "yes",
+			},
+			want: Config{ComponentLevels: map[string]string{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{
+				"LOG_LEVEL", "LOG_LEVELS", "LOG_OUTPUT", "LOG_FORMAT", "LOG_TIME_FORMAT", "LOG_REDACT_KEYS",
+				"LOG_MASK_KEYS", "LOG_TRUSTED_PROXIES", "LOG_TOKEN_KEY", "LOG_REDACT_BYPASS", "LOG_REDACT_BYPASS_ENVS",
+				"LOG_ALWAYS_REDACT_KEYS", "LOG_MAX_REQUEST_BODY", "LOG_MAX_RESPONSE_BODY", "LOG_CALLER",
+				"LOG_DISABLE_STACK", "DD_SERVICE", "DD_ENV", "DD_VERSION",
+			} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			if got := ConfigFromEnv(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ConfigFromEnv() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTimeFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		timeFormat string
+		want       string
+	}{
+		{name: "Should default to unix", timeFormat: "", want: zerolog.TimeFormatUnix},
+		{name: "Should parse unix", timeFormat: "unix", want: zerolog.TimeFormatUnix},
+		{name: "Should parse unixms", timeFormat: "unixms", want: zerolog.TimeFormatUnixMs},
+		{name: "Should parse unixmicro", timeFormat: "unixmicro", want: zerolog.TimeFormatUnixMicro},
+		{name: "Should parse unixnano", timeFormat: "unixnano", want: zerolog.TimeFormatUnixNano},
+		{name: "Should parse rfc3339 in any case", timeFormat: "RFC3339", want: time.RFC3339},
+		{name: "Should parse rfc3339nano", timeFormat: "rfc3339nano", want: time.RFC3339Nano},
+		{name: "Should keep a time layout", timeFormat: "2006-01-02", want: "2006-01-02"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseTimeFormat(tt.timeFormat); got != tt.want {
+				t.Errorf("parseTimeFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInit(t *testing.T) {
+	saveGlobals(t)
+
+	SetDefault(newInitLogger())
+
+	out := &bytes.Buffer{}
+	custom := zerolog.New(out)
+	log.Logger = custom
+
+	Init("warn")
+
+	if zerolog.GlobalLevel() != zerolog.WarnLevel {
+		t.Errorf("global level = %v, want warn", zerolog.GlobalLevel())
+	}
+
+	if !reflect.DeepEqual(log.Logger, custom) {
+		t.Error("Init() replaced log.Logger, want it kept")
+	}
+
+	Info(context.Background()).Msg("filtered")
+	Warn(context.Background()).Msg("written")
+
+	if strings.Contains(out.String(), "filtered") || !strings.Contains(out.String(), "written") {
+		t.Errorf("logs = %s, want the package functions written through log.Logger at the global level", out.String())
+	}
+}
+
+func TestInit_runtimeLevel(t *testing.T) {
+	saveGlobals(t)
+	SetDefault(newInitLogger())
+
+	var out syncBuffer
+	log.Logger = zerolog.New(&out)
+
+	Init("info")
+
+	handler := LevelHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/log-level", nil))
+
+	if strings.TrimSpace(rec.Body.String()) != `{"level":"info"}` {
+		t.Errorf("GET level = %s, want info", rec.Body.String())
+	}
+
+	Debug(context.Background()).Msg("before")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/log-level", strings.NewReader(`{"level":"debug","ttl":"50ms"}`)))
+
+	Debug(context.Background()).Msg("after")
+
+	if strings.Contains(out.String(), "before") || !strings.Contains(out.String(), "after") {
+		t.Errorf("logs = %s, want the debug logs after the change only", out.String())
+	}
+
+	if zerolog.GlobalLevel() != zerolog.DebugLevel {
+		t.Errorf("global level = %v, want debug", zerolog.GlobalLevel())
+	}
+
+	// The revert is audited after the level is set back.
+	deadline := time.Now().Add(2 * time.Second)
+	for strings.Count(out.String(), "Log level changed") != 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if zerolog.GlobalLevel() != zerolog.InfoLevel {
+		t.Errorf("global level after the ttl = %v, want info", zerolog.GlobalLevel())
+	}
+
+	SetComponentLevel(ComponentGorm, "debug")
+	Component(ComponentGorm).Debug(context.Background()).Msg("gorm query")
+	Component(ComponentHttpClient).Debug(context.Background()).Msg("client request")
+
+	if !strings.Contains(out.String(), `{"level":"debug","component":"gorm","message":"gorm query"}`) || strings.Contains(out.String(), "client request") {
+		t.Errorf("logs = %s, want the debug logs of the component below the global level", out.String())
+	}
+}
+
+func TestInitWithConfig(t *testing.T) {
+	saveGlobals(t)
+
+	out := &bytes.Buffer{}
+	log.Logger = zerolog.New(&bytes.Buffer{})
+
+	InitWithConfig(Config{
+		Level:           "warn",
+		Writer:          out,
+		TimeFormat:      "rfc3339",
+		Service:         "invoice-api",
+		Env:             "production",
+		Version:         "1.2.3",
+		Fields:          map[string]interface{}{"team": "payments"},
+		MaxRequestBody:  1024,
+		MaxResponseBody: -1,
+		DisableStack:    true,
+	})
+
+	if zerolog.TimeFieldFormat != time.RFC3339 || zerolog.ErrorStackMarshaler != nil {
+		t.Errorf("time format = %q and stack marshaler set = %v, want rfc3339 without stack", zerolog.TimeFieldFormat, zerolog.ErrorStackMarshaler != nil)
+	}
+
+	if maxRequestBodyBytes.Load() != 1024 || maxResponseBodyBytes.Load() != -1 {
+		t.Errorf("body limits = %d and %d, want 1024 and -1", maxRequestBodyBytes.Load(), maxResponseBodyBytes.Load())
+	}
+
+	Info(context.Background()).Msg("filtered")
+	Error(context.Background(), errors.New("failed")).Msg("from the package")
+	log.Warn().Msg("from log.Logger")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("logs = %s, want the error and the warn of log.Logger", out.String())
+	}
+
+	for _, line := range lines {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid log %q: %v", line, err)
+		}
+
+		if entry["service"] != "invoice-api" || entry["env"] != "production" || entry["version"] != "1.2.3" || entry["team"] != "payments" {
+			t.Errorf("log = %v, want the static fields of the config", entry)
+		}
+
+		if _, err := time.Parse(time.RFC3339, entry["time"].(string)); err != nil {
+			t.Errorf("time = %v, want rfc3339", entry["time"])
+		}
+	}
+}
+
+func TestInitWithConfig_logLoggerLevel(t *testing.T) {
+	saveGlobals(t)
+
+	out := &bytes.Buffer{}
+	InitWithConfig(Config{Level: "info", Writer: out})
+
+	log.Debug().Msg("before")
+
+	if out.Len() != 0 {
+		t.Errorf("logs = %s, want the debug of log.Logger filtered before the level change", out.String())
+	}
+
+	Default().ChangeLevel(context.Background(), zerolog.DebugLevel, 0, "test")
+	out.Reset()
+
+	log.Debug().Msg("after")
+
+	if !strings.Contains(out.String(), "after") {
+		t.Errorf("logs = %s, want the debug of log.Logger after the level change", out.String())
+	}
+}
@@ -0,0 +1,125 @@
+package liberlogger
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+var errUnsupportedEncoding = errors.New("unsupported content encoding")
+
+// logBody decodes the bytes read of a body for the logs: decompressed, on a copy, by the Content-Encoding
+// and decoded by the Content-Type. A body past the limit is returned as a truncatedBody.
+func logBody(header http.Header, data []byte, truncated bool, size int64, limit int64) (interface{}, error) {
+	if encoding := header.Get("Content-Encoding"); encoding != "" && !strings.EqualFold(encoding, "identity") {
+		decoded, decodedTruncated, decodedSize, err := decompress(encoding, data, truncated, limit)
+		if errors.Is(err, errUnsupportedEncoding) {
+			return map[string]interface{}{"content_encoding": encoding, "size": size}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		data, truncated, size = decoded, decodedTruncated, decodedSize
+	}
+
+	if truncated {
+		return truncatedBody{contentType: header.Get("Content-Type"), data: data, size: size}, nil
+	}
+
+	return decodeBody(header.Get("Content-Type"), data)
+}
+
+// decompress decodes the encodings, as "gzip" or "deflate, br", chaining the decoders so only the limit of
+// the decoded body is read, whatever the number of encodings. The start of a truncated body is decoded as
+// far as it goes, with an unknown size.
+func decompress(encoding string, data []byte, truncated bool, limit int64) ([]byte, bool, int64, error) {
+	encodings := strings.Split(encoding, ",")
+
+	reader := io.Reader(bytes.NewReader(data))
+
+	for i := len(encodings) - 1; i >= 0; i-- {
+		decoder, err := decompressReader(strings.ToLower(strings.TrimSpace(encodings[i])), reader)
+		if err != nil {
+			if truncated && (errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)) {
+				return nil, true, -1, nil
+			}
+
+			return nil, false, 0, err
+		}
+		defer decoder.Close()
+
+		reader = decoder
+	}
+
+	data, truncated, err := readDecoded(reader, limit, truncated)
+	if err != nil {
+		return nil, false, 0, err
+	}
+
+	size := int64(len(data))
+	if truncated {
+		size = -1
+	}
+
+	return data, truncated, size, nil
+}
+
+func readDecoded(reader io.Reader, limit int64, truncated bool) ([]byte, bool, error) {
+	if limit >= 0 {
+		reader = io.LimitReader(reader, limit+1)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil && !(truncated && errors.Is(err, io.ErrUnexpectedEOF)) {
+		return nil, false, err
+	}
+
+	if limit >= 0 && int64(len(data)) > limit {
+		return data[:limit], true, nil
+	}
+
+	return data, truncated, nil
+}
+
+func decompressReader(encoding string, reader io.Reader) (io.ReadCloser, error) {
+	switch encoding {
+	case "gzip", "x-gzip":
+		return gzip.NewReader(reader)
+	case "deflate":
+		// The deflate of HTTP is zlib, but some servers send the raw deflate.
+		buffered := bufio.NewReader(reader)
+
+		if header, _ := buffered.Peek(2); isZlibHeader(header) {
+			return zlib.NewReader(buffered)
+		}
+
+		return flate.NewReader(buffered), nil
+	case "br":
+		return io.NopCloser(brotli.NewReader(reader)), nil
+	case "zstd":
+		decoder, err := zstd.NewReader(reader, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+
+		return decoder.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("%w %q", errUnsupportedEncoding, encoding)
+	}
+}
+
+// isZlibHeader reports whether the bytes are a zlib header of the deflate method, as RFC 1950.
+func isZlibHeader(header []byte) bool {
+	return len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0
+}
@@ -0,0 +1,214 @@
+package liberlogger
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+func compressBody(t *testing.T, encoding string, body string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var writer io.WriteCloser
+
+	switch encoding {
+	case "gzip":
+		writer = gzip.NewWriter(&buf)
+	case "deflate":
+		writer = zlib.NewWriter(&buf)
+	case "br":
+		writer = brotli.NewWriter(&buf)
+	case "zstd":
+		encoder, err := zstd.NewWriter(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writer = encoder
+	}
+
+	if _, err := writer.Write([]byte(body)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestHttpClient_contentEncoding(t *testing.T) {
+	for _, encoding := range []string{"gzip", "deflate", "br", "zstd"} {
+		t.Run("Should log the "+encoding+" body decoded and leave the response as sent", func(t *testing.T) {
+			var out bytes.Buffer
+
+			compressed := compressBody(t, encoding, `{"id": 1, "access_token": "secret"}`)
+
+			client := &http.Client{
+				Transport: HttpClient{
+					Proxied: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+						return &http.Response{
+							StatusCode:    http.StatusOK,
+							Header:        http.Header{"Content-Type": []string{"application/json"}, "Content-Encoding": []string{encoding}},
+							Body:          io.NopCloser(bytes.NewReader(compressed)),
+							ContentLength: int64(len(compressed)),
+							Request:       req,
+						}, nil
+					}),
+					RedactedKeys: []string{"access_token"},
+				},
+			}
+
+			req, _ := http.NewRequestWithContext(New(WithOutput(&out)).WithContext(context.Background()), http.MethodGet, "https://partner.com/token", nil)
+			req.Header.Set("Accept-Encoding", encoding)
+
+			res, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+
+			if body, _ := io.ReadAll(res.Body); !bytes.Equal(body, compressed) || res.Header.Get("Content-Encoding") != encoding {
+				t.Errorf("response = %q with encoding %q, want the body and header as sent", body, res.Header.Get("Content-Encoding"))
+			}
+
+			var logs []map[string]interface{}
+
+			scanner := bufio.NewScanner(&out)
+			for scanner.Scan() {
+				var log map[string]interface{}
+				if err := json.Unmarshal(scanner.Bytes(), &log); err != nil {
+					t.Fatalf("invalid log %q: %v", scanner.Text(), err)
+				}
+				logs = append(logs, log)
+			}
+
+			want := map[string]interface{}{"id": float64(1), "access_token": REDACTED}
+			if len(logs) != 2 || !reflect.DeepEqual(logs[1]["body"], want) {
+				t.Errorf("logs = %v, want the response body %v", logs, want)
+			}
+		})
+	}
+}
+
+func TestLogBody_truncatedEncoding(t *testing.T) {
+	compressed := compressBody(t, "gzip", `{"id": 1, "description": "`+string(bytes.Repeat([]byte("a"), 1000))+`"}`)
+	header := http.Header{"Content-Type": []string{"application/json"}, "Content-Encoding": []string{"gzip"}}
+
+	body, err := logBody(header, compressed, false, int64(len(compressed)), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := truncatedBody{contentType: "application/json", data: []byte(`{"id": 1, `), size: -1}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("logBody() = %#v, want %#v", body, want)
+	}
+
+	body, err = logBody(http.Header{"Content-Encoding": []string{"compress"}}, compressed, false, 20, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := map[string]interface{}{"content_encoding": "compress", "size": int64(20)}; !reflect.DeepEqual(body, want) {
+		t.Errorf("logBody() = %#v, want %#v", body, want)
+	}
+}
+
+func TestDecompress(t *testing.T) {
+	var rawDeflate bytes.Buffer
+
+	writer, _ := flate.NewWriter(&rawDeflate, flate.DefaultCompression)
+	_, _ = writer.Write([]byte(`{"id":1}`))
+	_ = writer.Close()
+
+	tests := []struct {
+		name          string
+		encoding      string
+		data          []byte
+		limit         int64
+		want          string
+		wantTruncated bool
+	}{
+		{
+			name:     "Should decode the stacked encodings",
+			encoding: "gzip, br",
+			data:     compressBody(t, "br", string(compressBody(t, "gzip", `{"id":1}`))),
+			limit:    1024,
+			want:     `{"id":1}`,
+		},
+		{
+			name:     "Should decode the raw deflate",
+			encoding: "deflate",
+			data:     rawDeflate.Bytes(),
+			limit:    1024,
+			want:     `{"id":1}`,
+		},
+		{
+			name:          "Should truncate the stacked encodings to the limit",
+			encoding:      "gzip, zstd",
+			data:          compressBody(t, "zstd", string(compressBody(t, "gzip", `{"id":1,"name":"John"}`))),
+			limit:         8,
+			want:          `{"id":1,`,
+			wantTruncated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, truncated, _, err := decompress(tt.encoding, tt.data, false, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(data) != tt.want || truncated != tt.wantTruncated {
+				t.Errorf("decompress() = %q, %v, want %q, %v", data, truncated, tt.want, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestDecompress_bomb(t *testing.T) {
+	const size = 64 << 20
+
+	// The gzip without compression of 64MB of zeros is as large, and the brotli of it is a few KB, so the
+	// brotli layer is the bomb.
+	var stored bytes.Buffer
+
+	writer, _ := gzip.NewWriterLevel(&stored, gzip.NoCompression)
+	_, _ = writer.Write(make([]byte, size))
+	_ = writer.Close()
+
+	bomb := compressBody(t, "br", stored.String())
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	data, truncated, _, err := decompress("gzip, br", bomb, false, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runtime.ReadMemStats(&after)
+
+	if len(data) != 1024 || !truncated {
+		t.Errorf("decompress() = %d bytes, %v, want the 1024 bytes of the limit truncated", len(data), truncated)
+	}
+
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 16<<20 {
+		t.Errorf("decompress() allocated %d bytes, want the layers bounded by the limit", allocated)
+	}
+}
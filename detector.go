@@ -0,0 +1,180 @@
+package liberlogger
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Detector finds sensitive values by their content, regardless of the key holding them, as a CPF inside
+// a description. The matches are masked or replaced by REDACTED, according to the Action.
+type Detector struct {
+	Name     string
+	Pattern  *regexp.Regexp
+	Validate func(match string) bool // Optional, discards the matches it returns false for.
+	Action   Action
+	Masker   Masker // Masks the matches of ActionMask. Defaults to GenericMasker.
+}
+
+// RedactorOption configures a Redactor.
+type RedactorOption func(*Redactor)
+
+// WithDetectors adds detectors scanning the string values not selected by the rules. They run in order.
+func WithDetectors(detectors ...Detector) RedactorOption {
+	return func(r *Redactor) {
+		r.detectors = append(r.detectors, detectors...)
+	}
+}
+
+var (
+	cpfPattern    = regexp.MustCompile(`\b\d{3}\.?\d{3}\.?\d{3}-?\d{2}\b`)
+	cnpjPattern   = regexp.MustCompile(`\b\d{2}\.?\d{3}\.?\d{3}/?\d{4}-?\d{2}\b`)
+	panPattern    = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	phonePattern  = regexp.MustCompile(`(?:\+55\s?)?(?:\(\d{2}\)|\b\d{2})\s?9?\d{4}[-\s]?\d{4}\b`)
+	jwtPattern    = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	bearerPattern = regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`)
+)
+
+func CPFDetector(action Action) Detector {
+	return Detector{Name: "cpf", Pattern: cpfPattern, Validate: validCPF, Action: action, Masker: CPFMasker}
+}
+
+func CNPJDetector(action Action) Detector {
+	return Detector{Name: "cnpj", Pattern: cnpjPattern, Validate: validCNPJ, Action: action, Masker: CNPJMasker}
+}
+
+// PANDetector detects the credit card numbers, validated by the Luhn algorithm.
+func PANDetector(action Action) Detector {
+	return Detector{Name: "pan", Pattern: panPattern, Validate: validLuhn, Action: action, Masker: PANMasker}
+}
+
+func EmailDetector(action Action) Detector {
+	return Detector{Name: "email", Pattern: emailPattern, Action: action, Masker: EmailMasker}
+}
+
+// PhoneDetector detects the Brazilian phone numbers, with or without the +55 country code.
+func PhoneDetector(action Action) Detector {
+	return Detector{Name: "phone", Pattern: phonePattern, Action: action, Masker: PhoneMasker}
+}
+
+func JWTDetector(action Action) Detector {
+	return Detector{Name: "jwt", Pattern: jwtPattern, Action: action}
+}
+
+func BearerDetector(action Action) Detector {
+	return Detector{Name: "bearer", Pattern: bearerPattern, Action: action}
+}
+
+// DefaultDetectors returns every built-in detector with the action. The documents run before the phone
+// numbers, which would match an unformatted CPF.
+func DefaultDetectors(action Action) []Detector {
+	return []Detector{
+		JWTDetector(action),
+		BearerDetector(action),
+		EmailDetector(action),
+		CNPJDetector(action),
+		CPFDetector(action),
+		PANDetector(action),
+		PhoneDetector(action),
+	}
+}
+
+// detect applies the detectors to the value. The bypass skips the redact detectors, as it does the rules.
+func (r *Redactor) detect(value string, bypass bool) string {
+	for _, detector := range r.detectors {
+		if bypass && detector.Action == ActionRedact {
+			continue
+		}
+
+		value = detector.Pattern.ReplaceAllStringFunc(value, func(match string) string {
+			if detector.Validate != nil && !detector.Validate(match) {
+				return match
+			}
+
+			return compiledRule{action: detector.Action, masker: detector.Masker}.apply(match)
+		})
+	}
+
+	return value
+}
+
+func digits(value string) []int {
+	result := make([]int, 0, len(value))
+
+	for _, c := range value {
+		if c >= '0' && c <= '9' {
+			result = append(result, int(c-'0'))
+		}
+	}
+
+	return result
+}
+
+func allEqual(numbers []int) bool {
+	for _, n := range numbers {
+		if n != numbers[0] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkDigit computes a modulo 11 check digit of the Brazilian documents.
+func checkDigit(numbers []int, weights []int) int {
+	sum := 0
+
+	for i, weight := range weights {
+		sum += numbers[i] * weight
+	}
+
+	if rest := sum % 11; rest >= 2 {
+		return 11 - rest
+	}
+
+	return 0
+}
+
+func validCPF(value string) bool {
+	numbers := digits(value)
+	if len(numbers) != 11 || allEqual(numbers) {
+		return false
+	}
+
+	return checkDigit(numbers, []int{10, 9, 8, 7, 6, 5, 4, 3, 2}) == numbers[9] &&
+		checkDigit(numbers, []int{11, 10, 9, 8, 7, 6, 5, 4, 3, 2}) == numbers[10]
+}
+
+func validCNPJ(value string) bool {
+	numbers := digits(value)
+	if len(numbers) != 14 || allEqual(numbers) {
+		return false
+	}
+
+	return checkDigit(numbers, []int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}) == numbers[12] &&
+		checkDigit(numbers, []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}) == numbers[13]
+}
+
+func validLuhn(value string) bool {
+	numbers := digits(strings.TrimSpace(value))
+	if len(numbers) < 13 || len(numbers) > 19 {
+		return false
+	}
+
+	sum := 0
+
+	for i := range numbers {
+		n := numbers[len(numbers)-1-i]
+
+		if i%2 == 1 {
+			n *= 2
+			if n > 9 {
+				n -= 9
+			}
+		}
+
+		sum += n
+	}
+
+	return sum%10 == 0
+}
@@ -0,0 +1,87 @@
+package liberlogger
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestRedactor_detectors(t *testing.T) {
+	tests := []struct {
+		name      string
+		detectors []Detector
+		body      interface{}
+		want      interface{}
+	}{
+		{
+			name:      "Should redact a CPF in a free text",
+			detectors: DefaultDetectors(ActionRedact),
+			body:      map[string]interface{}{"description": "payer 529.982.247-25 paid"},
+			want:      map[string]interface{}{"description": "payer " + REDACTED + " paid"},
+		},
+		{
+			name:      "Should ignore a CPF with invalid check digits",
+			detectors: []Detector{CPFDetector(ActionRedact)},
+			body:      map[string]interface{}{"description": "order 529.982.247-26"},
+			want:      map[string]interface{}{"description": "order 529.982.247-26"},
+		},
+		{
+			name:      "Should mask a CNPJ",
+			detectors: DefaultDetectors(ActionMask),
+			body:      map[string]interface{}{"notes": "11222333000181"},
+			want:      map[string]interface{}{"notes": "11******000181"},
+		},
+		{
+			name:      "Should redact a card number validated by Luhn",
+			detectors: []Detector{PANDetector(ActionRedact)},
+			body:      map[string]interface{}{"notes": "card 4111 1111 1111 1111, ref 4111111111111112"},
+			want:      map[string]interface{}{"notes": "card " + REDACTED + ", ref 4111111111111112"},
+		},
+		{
+			name:      "Should redact emails, phones and tokens",
+			detectors: DefaultDetectors(ActionRedact),
+			body: map[string]interface{}{
+				"error":         "user john.doe@liber.com, phone +55 (11) 98765-4321",
+				"Authorization": "Bearer abc.def-123",
+				"token":         "eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.sig",
+			},
+			want: map[string]interface{}{
+				"error":         "user " + REDACTED + ", phone " + REDACTED,
+				"Authorization": REDACTED,
+				"token":         REDACTED,
+			},
+		},
+		{
+			name:      "Should detect in the items of arrays and plain texts",
+			detectors: []Detector{EmailDetector(ActionRedact)},
+			body:      []interface{}{"to john@liber.com", map[string]interface{}{"list": []string{"ana@liber.com"}}},
+			want: []interface{}{
+				map[string]interface{}{"plain/text-type": "to " + REDACTED},
+				map[string]interface{}{"list": []interface{}{REDACTED}},
+			},
+		},
+		{
+			name:      "Should use a custom detector",
+			detectors: []Detector{{Name: "account", Pattern: regexp.MustCompile(`ACC-\d+`)}},
+			body:      map[string]interface{}{"notes": "account ACC-123"},
+			want:      map[string]interface{}{"notes": "account " + REDACTED},
+		},
+		{
+			name: "Should not detect without detectors",
+			body: map[string]interface{}{"notes": "529.982.247-25"},
+			want: map[string]interface{}{"notes": "529.982.247-25"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redactor, err := NewRedactor(nil, WithDetectors(tt.detectors...))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := redactor.Redact(tt.body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Redact() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,74 +1,49 @@
 package liberlogger
 
 import (
+	"errors"
+	"net/http"
+
 	"github.com/labstack/echo/v4"
-	"github.com/rs/zerolog/log"
 )
 
+// EchoV4 logs the requests and responses with the DefaultKeys redacted and the DefaultKeysToMask masked,
+// as EchoV4Middleware.
 func EchoV4(routesIgnore []string) func(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c echo.Context) error {
-			var body interface{}
-
-			ctx := c.Request().Context()
-
-			if ignoreRoute(routesIgnore, c.Request()) {
-				return next(c)
-			}
+	return EchoV4Middleware(MiddlewareIgnoreRoutes(routesIgnore...))
+}
 
-			err := extractBody(c.Request(), &body)
+// EchoV4Redacted logs the requests and responses with the keys redacted and masked, as EchoV4Middleware.
+func EchoV4Redacted(redactKeys []string, maskKeys []string, routesIgnore []string) func(next echo.HandlerFunc) echo.HandlerFunc {
+	return EchoV4Middleware(MiddlewareRedactKeys(redactKeys, maskKeys), MiddlewareIgnoreRoutes(routesIgnore...))
+}
 
-			if err != nil {
-				Error(ctx, err).
-					Interface("headers", parseHeaders(c.Request().Header)).
-					Interface("body", Redact([]string{}, []string{}, body)).
-					Dict("extra", extraLogs(c.Request(), err)).
-					Msg(formatFinalMsg(c.Request(), "HTTP Server | Error when parse in liberlogger"))
+// EchoV4Middleware is the HTTPMiddleware of echo, logging the handler errors with the status echo responds.
+func EchoV4Middleware(opts ...MiddlewareOption) func(next echo.HandlerFunc) echo.HandlerFunc {
+	m := newMiddleware(append([]MiddlewareOption{MiddlewareComponent(ComponentEcho)}, opts...))
+	m.errorStatus = func(status int, err error) int { return echoErrorStatus(err) }
 
-				return next(c)
-			}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			return m.serve(c.Response().Writer, c.Request(), func(w http.ResponseWriter, r *http.Request) (string, error) {
+				c.Response().Writer = w
+				c.SetRequest(r)
 
-			Info(ctx).
-				Interface("headers", parseHeaders(c.Request().Header)).
-				Interface("body", Redact([]string{}, []string{}, body)).
-				Dict("extra", extraLogs(c.Request(), nil)).
-				Msg(formatFinalMsg(c.Request(), "HTTP Server"))
+				err := next(c)
 
-			return next(c)
+				return c.Path(), err
+			})
 		}
 	}
 }
 
-func EchoV4Redacted(redactKeys []string, maskKeys []string, routesIgnore []string) func(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c echo.Context) error {
-			var body interface{}
-
-			ctx := log.Logger.WithContext(c.Request().Context())
-
-			if ignoreRoute(routesIgnore, c.Request()) {
-				return next(c)
-			}
-
-			err := extractBody(c.Request(), &body)
+// echoErrorStatus resolves the status code echo will respond for the handler error.
+func echoErrorStatus(err error) int {
+	var httpError *echo.HTTPError
 
-			if err != nil {
-				Error(ctx, err).
-					Interface("headers", Redact(redactKeys, maskKeys, parseHeaders(c.Request().Header))).
-					Interface("body", Redact(redactKeys, maskKeys, body)).
-					Dict("extra", extraLogs(c.Request(), err)).
-					Msg(formatFinalMsg(c.Request(), "HTTP Server | Error when parse in liberlogger"))
-
-				return next(c)
-			}
-
-			Info(ctx).
-				Interface("headers", Redact(redactKeys, maskKeys, parseHeaders(c.Request().Header))).
-				Interface("body", Redact(redactKeys, maskKeys, body)).
-				Dict("extra", extraLogs(c.Request(), nil)).
-				Msg(formatFinalMsg(c.Request(), "HTTP Server"))
-
-			return next(c)
-		}
+	if errors.As(err, &httpError) {
+		return httpError.Code
 	}
+
+	return http.StatusInternalServerError
 }
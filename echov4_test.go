@@ -0,0 +1,135 @@
+package liberlogger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestEchoV4Redacted(t *testing.T) {
+	tests := []struct {
+		name         string
+		handler      echo.HandlerFunc
+		wantLevel    string
+		wantStatus   float64
+		wantRespBody interface{}
+	}{
+		{
+			name: "Should log the redacted response",
+			handler: func(c echo.Context) error {
+				return c.JSON(http.StatusCreated, map[string]interface{}{"id": "1", "password": "secret"})
+			},
+			wantLevel:    "info",
+			wantStatus:   http.StatusCreated,
+			wantRespBody: map[string]interface{}{"id": "1", "password": REDACTED},
+		},
+		{
+			name: "Should log the handler error with the status of the echo.HTTPError",
+			handler: func(c echo.Context) error {
+				return echo.NewHTTPError(http.StatusNotFound, "not found")
+			},
+			wantLevel:  "warn",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "Should log the handler error as internal server error",
+			handler: func(c echo.Context) error {
+				return errors.New("database is down")
+			},
+			wantLevel:  "error",
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			logger := New(WithOutput(&out))
+
+			e := echo.New()
+			e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					c.SetRequest(c.Request().WithContext(logger.WithContext(c.Request().Context())))
+					return next(c)
+				}
+			})
+			e.Use(EchoV4Redacted([]string{"password"}, []string{}, []string{}))
+			e.POST("/users", tt.handler)
+
+			req := httptest.NewRequest(http.MethodPost, "/users?password=secret", strings.NewReader(`{"password":"secret"}`))
+			e.ServeHTTP(httptest.NewRecorder(), req)
+
+			if strings.Contains(out.String(), "secret") {
+				t.Errorf("logs = %s, want the password redacted from the body and URL", out.String())
+			}
+
+			var logs []map[string]interface{}
+
+			scanner := bufio.NewScanner(&out)
+			for scanner.Scan() {
+				var log map[string]interface{}
+				if err := json.Unmarshal(scanner.Bytes(), &log); err != nil {
+					t.Fatalf("invalid log %q: %v", scanner.Text(), err)
+				}
+				logs = append(logs, log)
+			}
+
+			if len(logs) != 2 {
+				t.Fatalf("logs = %v, want the request and the response", logs)
+			}
+
+			if body := logs[0]["body"].(map[string]interface{}); body["password"] != REDACTED {
+				t.Errorf("request body = %v, want the password redacted", body)
+			}
+
+			response := logs[1]
+			extra := response["extra"].(map[string]interface{})
+
+			if response["level"] != tt.wantLevel || extra["status"] != tt.wantStatus {
+				t.Errorf("response level = %v and status = %v, want %v and %v", response["level"], extra["status"], tt.wantLevel, tt.wantStatus)
+			}
+
+			if _, ok := extra[FieldDuration]; !ok {
+				t.Errorf("extra = %v, want the duration", extra)
+			}
+
+			if tt.wantRespBody != nil && !reflect.DeepEqual(response["body"], tt.wantRespBody) {
+				t.Errorf("response body = %v, want %v", response["body"], tt.wantRespBody)
+			}
+		})
+	}
+}
+
+func TestEchoV4Flush(t *testing.T) {
+	e := echo.New()
+	e.Use(EchoV4Middleware())
+	e.GET("/events", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+		c.Response().WriteHeader(http.StatusOK)
+
+		if _, err := c.Response().Write([]byte("data: 1\n\n")); err != nil {
+			return err
+		}
+
+		c.Response().Flush()
+
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	e.ServeHTTP(rec, req.WithContext(New(WithOutput(io.Discard)).WithContext(req.Context())))
+
+	if !rec.Flushed || rec.Body.String() != "data: 1\n\n" {
+		t.Errorf("flushed = %v and body = %q, want the event flushed", rec.Flushed, rec.Body.String())
+	}
+}
@@ -1,15 +1,8 @@
 package liberlogger
 
 import (
-	"bytes"
-	"encoding/json"
 	"errors"
-	"io"
 	"net/http"
-	"reflect"
-
-	"github.com/kataras/compress"
-	"github.com/rs/zerolog"
 )
 
 func parseHeaders(headers map[string][]string) map[string]string {
@@ -22,76 +15,48 @@ func parseHeaders(headers map[string][]string) map[string]string {
 	return newHeaders
 }
 
-func extractBody(request interface{}, parseTo interface{}) error {
+// extractBody reads the body of the *http.Request or *http.Response up to the limit, leaving every byte
+// in its place, as sent, and decodes a copy for the logs as logBody.
+func extractBody(request interface{}, limit int64) (interface{}, error) {
 	var bodyBytes []byte
-
-	typeParse := reflect.ValueOf(parseTo)
-
-	if typeParse.Kind() != reflect.Ptr {
-		return errors.New("parseTo must be a pointer")
-	}
+	var header http.Header
+	var size int64
+	var truncated bool
 
 	switch request := request.(type) {
 	case *http.Request:
 		var err error
 
 		if request.Body == nil {
-			return nil
+			return nil, nil
 		}
 
-		bodyBytes, err = io.ReadAll(request.Body)
+		bodyBytes, request.Body, truncated, err = readBody(request.Body, limit)
 
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		defer request.Body.Close()
-
-		request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+		header = request.Header
+		size = request.ContentLength
 	case *http.Response:
 		var err error
 
 		if request.Body == nil {
-			return nil
-		}
-
-		if request.Header.Get("Content-Encoding") == "deflate" {
-			reader, err := compress.NewReader(request.Body, compress.DEFLATE)
-			if err != nil {
-				return err
-			}
-			defer reader.Close()
-
-			request.Body = reader
+			return nil, nil
 		}
 
-		bodyBytes, err = io.ReadAll(request.Body)
+		bodyBytes, request.Body, truncated, err = readBody(request.Body, limit)
 
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		defer request.Body.Close()
-
-		request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+		header = request.Header
+		size = request.ContentLength
 	default:
-		return errors.New("request is not a valid type")
-	}
-
-	if len(bodyBytes) == 0 {
-		return nil
-	}
-
-	return json.Unmarshal(bodyBytes, parseTo)
-}
-
-func ignoreRedacted() bool {
-	zerologLevel := zerolog.GlobalLevel()
-
-	switch zerologLevel {
-	case zerolog.DebugLevel:
-		return true
+		return nil, errors.New("request is not a valid type")
 	}
 
-	return false
+	return logBody(header, bodyBytes, truncated, size, limit)
 }
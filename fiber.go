@@ -0,0 +1,69 @@
+package liberlogger
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+)
+
+// Fiber logs the requests and responses of a fiber app as HTTPMiddleware. The request is converted to a
+// *http.Request with the logger of c.UserContext, and the handler errors are logged with the status of
+// the *fiber.Error. The bodies of streamed responses are not logged.
+func Fiber(opts ...MiddlewareOption) fiber.Handler {
+	m := newMiddleware(append([]MiddlewareOption{MiddlewareComponent(ComponentFiber)}, opts...))
+	m.errorStatus = func(status int, err error) int { return fiberErrorStatus(err) }
+
+	return func(c *fiber.Ctx) error {
+		r := new(http.Request)
+		if err := fasthttpadaptor.ConvertRequest(c.Context(), r, true); err != nil {
+			return c.Next()
+		}
+
+		return m.serve(&fiberResponseWriter{header: http.Header{}}, r.WithContext(c.UserContext()), func(w http.ResponseWriter, r *http.Request) (string, error) {
+			err := c.Next()
+
+			response := c.Response()
+
+			response.Header.VisitAll(func(key, value []byte) {
+				w.Header().Add(string(key), string(value))
+			})
+
+			w.WriteHeader(response.StatusCode())
+
+			if !response.IsBodyStream() {
+				_, _ = w.Write(response.Body())
+			}
+
+			return c.Route().Path, err
+		})
+	}
+}
+
+// fiberErrorStatus resolves the status code fiber will respond for the handler error.
+func fiberErrorStatus(err error) int {
+	var fiberError *fiber.Error
+
+	if errors.As(err, &fiberError) {
+		return fiberError.Code
+	}
+
+	return http.StatusInternalServerError
+}
+
+// fiberResponseWriter receives a copy of the response set in the fasthttp context, for the
+// LogResponseWriter to log it.
+type fiberResponseWriter struct {
+	header http.Header
+}
+
+func (w *fiberResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *fiberResponseWriter) Write(body []byte) (int, error) {
+	return len(body), nil
+}
+
+func (w *fiberResponseWriter) WriteHeader(int) {}
@@ -0,0 +1,45 @@
+package liberlogger
+
+import (
+	"context"
+	"fmt"
+)
+
+// WithFields returns a copy of ctx with fields merged into the LogFieldsKey fields, without changing
+// the fields of the parent context.
+func WithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	merged := FieldsFromContext(ctx)
+
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	return context.WithValue(ctx, LogFieldsKey{}, merged)
+}
+
+// WithField returns a copy of ctx with the field merged into the LogFieldsKey fields.
+func WithField(ctx context.Context, key string, value interface{}) context.Context {
+	return WithFields(ctx, map[string]interface{}{key: value})
+}
+
+// FieldsFromContext returns a copy of the LogFieldsKey fields of ctx.
+func FieldsFromContext(ctx context.Context) map[string]interface{} {
+	fields := map[string]interface{}{}
+
+	switch value := ctx.Value(LogFieldsKey{}).(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			fields[key] = field
+		}
+	case map[string]string:
+		for key, field := range value {
+			fields[key] = field
+		}
+	case []interface{}:
+		for i := 0; i+1 < len(value); i += 2 {
+			fields[fmt.Sprint(value[i])] = value[i+1]
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,56 @@
+package liberlogger
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestWithFields(t *testing.T) {
+	tests := []struct {
+		name       string
+		parent     interface{}
+		fields     map[string]interface{}
+		want       map[string]interface{}
+		wantParent map[string]interface{}
+	}{
+		{
+			name:       "Should add fields to a context without fields",
+			fields:     map[string]interface{}{"dd.trace_id": 123},
+			want:       map[string]interface{}{"dd.trace_id": 123},
+			wantParent: map[string]interface{}{},
+		},
+		{
+			name:       "Should merge fields without changing the parent",
+			parent:     map[string]interface{}{"partner": "bank", "log_id": "1"},
+			fields:     map[string]interface{}{"log_id": "2", "dd.trace_id": 123},
+			want:       map[string]interface{}{"partner": "bank", "log_id": "2", "dd.trace_id": 123},
+			wantParent: map[string]interface{}{"partner": "bank", "log_id": "1"},
+		},
+		{
+			name:       "Should merge fields set as map[string]string",
+			parent:     map[string]string{"partner": "bank"},
+			fields:     map[string]interface{}{"log_id": "2"},
+			want:       map[string]interface{}{"partner": "bank", "log_id": "2"},
+			wantParent: map[string]interface{}{"partner": "bank"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := context.Background()
+			if tt.parent != nil {
+				parent = context.WithValue(parent, LogFieldsKey{}, tt.parent)
+			}
+
+			ctx := WithFields(parent, tt.fields)
+
+			if got := FieldsFromContext(ctx); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FieldsFromContext() = %+v, want %+v", got, tt.want)
+			}
+
+			if got := FieldsFromContext(parent); !reflect.DeepEqual(got, tt.wantParent) {
+				t.Errorf("FieldsFromContext(parent) = %+v, want %+v", got, tt.wantParent)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package liberlogger
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Gin logs the requests and responses of a gin engine as HTTPMiddleware. The last error added to the
+// context with c.Error is logged as the handler error, with the status written by the handler.
+func Gin(opts ...MiddlewareOption) gin.HandlerFunc {
+	m := newMiddleware(append([]MiddlewareOption{MiddlewareComponent(ComponentGin)}, opts...))
+	m.errorStatus = func(status int, err error) int { return status }
+
+	return func(c *gin.Context) {
+		writer := c.Writer
+
+		_ = m.serve(writer, c.Request, func(w http.ResponseWriter, r *http.Request) (string, error) {
+			c.Writer = &ginResponseWriter{ResponseWriter: writer, writer: w}
+			c.Request = r
+
+			c.Next()
+
+			c.Writer = writer
+
+			if err := c.Errors.Last(); err != nil {
+				return c.FullPath(), err
+			}
+
+			return c.FullPath(), nil
+		})
+	}
+}
+
+// ginResponseWriter writes the body and the status through the LogResponseWriter, keeping the methods of
+// the gin.ResponseWriter.
+type ginResponseWriter struct {
+	gin.ResponseWriter
+	writer http.ResponseWriter
+}
+
+func (w *ginResponseWriter) WriteHeader(code int) {
+	w.writer.WriteHeader(code)
+}
+
+func (w *ginResponseWriter) Write(body []byte) (int, error) {
+	return w.writer.Write(body)
+}
+
+func (w *ginResponseWriter) WriteString(s string) (int, error) {
+	return w.writer.Write([]byte(s))
+}
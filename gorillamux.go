@@ -1,119 +1,58 @@
 package liberlogger
 
 import (
+	"bufio"
 	"bytes"
+	"net"
 	"net/http"
+
+	"github.com/gorilla/mux"
 )
 
+// GorillaMux logs the requests and responses with the DefaultKeys redacted and the DefaultKeysToMask masked,
+// as HTTPMiddleware.
 func GorillaMux(routesIgnore []string) func(next http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			var body interface{}
-
-			ctx := r.Context()
-
-			if ignoreRoute(routesIgnore, r) {
-				next.ServeHTTP(w, r)
-				return
-			}
-
-			err := extractBody(r, &body)
-
-			logRespWriter := NewLogResponseWriter(w, r)
-
-			if err != nil {
-				Error(ctx, err).
-					Interface("headers", parseHeaders(r.Header)).
-					Interface("body", body).
-					Dict("extra", extraLogs(r, err)).
-					Msg(formatFinalMsg(r, "HTTP Server - Request | Error when parse in liberlogger"))
-
-				next.ServeHTTP(logRespWriter, r)
-
-				Info(ctx).
-					Interface("headers", parseHeaders(logRespWriter.Header())).
-					Interface("body", Redact([]string{}, []string{}, logRespWriter.buf)).
-					Dict("extra", extraLogs(r, nil)).
-					Msg(formatFinalMsg(r, "HTTP Server - Response |"))
-				return
-			}
-
-			Info(ctx).
-				Interface("headers", parseHeaders(r.Header)).
-				Interface("body", body).
-				Dict("extra", extraLogs(r, nil)).
-				Msg(formatFinalMsg(r, "HTTP Server - Request |"))
-
-			next.ServeHTTP(logRespWriter, r)
-
-			Info(ctx).
-				Interface("headers", parseHeaders(logRespWriter.Header())).
-				Interface("body", Redact([]string{}, []string{}, logRespWriter.buf)).
-				Dict("extra", extraLogs(r, nil)).
-				Msg(formatFinalMsg(logRespWriter, "HTTP Server - Response |"))
-		})
-	}
+	return HTTPMiddleware(
+		MiddlewareComponent(ComponentGorillaMux),
+		MiddlewareRoute(gorillaMuxRoute),
+		MiddlewareIgnoreRoutes(routesIgnore...),
+	)
 }
 
+// GorillaMuxRedacted logs the requests and responses with the keys redacted and masked, as HTTPMiddleware.
 func GorillaMuxRedacted(redactKeys []string, maskKeys []string, routesIgnore []string) func(next http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			var body interface{}
-
-			ctx := r.Context()
-
-			if ignoreRoute(routesIgnore, r) {
-				next.ServeHTTP(w, r)
-				return
-			}
-
-			err := extractBody(r, &body)
-
-			logRespWriter := NewLogResponseWriter(w, r)
-
-			if err != nil {
-				Error(ctx, err).
-					Interface("headers", Redact(redactKeys, maskKeys, parseHeaders(r.Header))).
-					Interface("body", Redact(redactKeys, maskKeys, body)).
-					Dict("extra", extraLogs(r, err)).
-					Msg(formatFinalMsg(r, "HTTP Server - Request | Error when parse in liberlogger"))
-
-				next.ServeHTTP(logRespWriter, r)
-
-				Info(ctx).
-					Interface("headers", Redact(redactKeys, maskKeys, parseHeaders(logRespWriter.Header()))).
-					Interface("body", Redact(redactKeys, maskKeys, logRespWriter.buf)).
-					Dict("extra", extraLogs(r, nil)).
-					Msg(formatFinalMsg(r, "HTTP Server - Response |"))
-				return
-			}
-
-			Info(ctx).
-				Interface("headers", Redact(redactKeys, maskKeys, parseHeaders(r.Header))).
-				Interface("body", Redact(redactKeys, maskKeys, body)).
-				Dict("extra", extraLogs(r, nil)).
-				Msg(formatFinalMsg(r, "HTTP Server - Request |"))
-
-			next.ServeHTTP(logRespWriter, r)
-
-			Info(ctx).
-				Interface("headers", Redact(redactKeys, maskKeys, parseHeaders(logRespWriter.Header()))).
-				Interface("body", Redact(redactKeys, maskKeys, logRespWriter.buf)).
-				Dict("extra", extraLogs(r, nil)).
-				Msg(formatFinalMsg(logRespWriter, "HTTP Server - Response |"))
-		})
+	return HTTPMiddleware(
+		MiddlewareComponent(ComponentGorillaMux),
+		MiddlewareRoute(gorillaMuxRoute),
+		MiddlewareRedactKeys(redactKeys, maskKeys),
+		MiddlewareIgnoreRoutes(routesIgnore...),
+	)
+}
+
+// gorillaMuxRoute returns the path template of the route matched by the router.
+func gorillaMuxRoute(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return ""
 	}
+
+	template, _ := route.GetPathTemplate()
+
+	return template
 }
 
 type LogResponseWriter struct {
 	http.ResponseWriter
 	StatusCode int
 	buf        bytes.Buffer
+	limit      int64
+	size       int64
 	Request    *http.Request
 }
 
+// NewLogResponseWriter wraps w, buffering the response body up to the limit of SetMaxBodyBytes.
 func NewLogResponseWriter(w http.ResponseWriter, r *http.Request) *LogResponseWriter {
-	return &LogResponseWriter{ResponseWriter: w, Request: r}
+	return &LogResponseWriter{ResponseWriter: w, Request: r, limit: maxResponseBodyBytes.Load()}
 }
 
 func (w *LogResponseWriter) WriteHeader(code int) {
@@ -121,7 +60,43 @@ func (w *LogResponseWriter) WriteHeader(code int) {
 	w.ResponseWriter.WriteHeader(code)
 }
 
+// decodedBody decodes the buffered body as logBody, nil when it fails.
+func (w *LogResponseWriter) decodedBody() interface{} {
+	truncated := w.size > int64(w.buf.Len())
+
+	body, err := logBody(w.Header(), w.buf.Bytes(), truncated, w.size, w.limit)
+	if err != nil {
+		return nil
+	}
+
+	return body
+}
+
 func (w *LogResponseWriter) Write(body []byte) (int, error) {
-	w.buf.Write(body)
-	return w.ResponseWriter.Write(body)
+	if buffered := int64(w.buf.Len()); w.limit < 0 || buffered+int64(len(body)) <= w.limit {
+		w.buf.Write(body)
+	} else if buffered < w.limit {
+		w.buf.Write(body[:w.limit-buffered])
+	}
+
+	n, err := w.ResponseWriter.Write(body)
+	w.size += int64(n)
+
+	return n, err
+}
+
+// Flush flushes the wrapped writer, when it supports http.Flusher, directly or by Unwrap.
+func (w *LogResponseWriter) Flush() {
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack hijacks the connection of the wrapped writer, failing with http.ErrNotSupported when it does not
+// support http.Hijacker.
+func (w *LogResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController.
+func (w *LogResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
 }
@@ -0,0 +1,130 @@
+package liberlogger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+// GormLogger implements the gorm logger.Interface, writing the SQL logs through liberlogger with the
+// component "gorm", so they carry the context fields and dd trace ids.
+type GormLogger struct {
+	SlowThreshold             time.Duration       // Queries slower than it are logged as warn. Zero disables it.
+	LogLevel                  gormlogger.LogLevel // Defaults to gormlogger.Info, leaving the filter to the component level.
+	IgnoreRecordNotFoundError bool
+	RedactParams              bool // Replaces the bound parameter values of the SQL with REDACTED.
+}
+
+// NewGormLogger returns a GormLogger with a slow threshold of 200ms.
+func NewGormLogger() GormLogger {
+	return GormLogger{
+		SlowThreshold: 200 * time.Millisecond,
+		LogLevel:      gormlogger.Info,
+	}
+}
+
+func (gl GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	gl.LogLevel = level
+	return gl
+}
+
+func (gl GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if gl.level() >= gormlogger.Info {
+		gormComponent(ctx).Info(ctx).Str("caller", utils.FileWithLineNum()).Msgf("GORM | "+msg, data...)
+	}
+}
+
+func (gl GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if gl.level() >= gormlogger.Warn {
+		gormComponent(ctx).Warn(ctx).Str("caller", utils.FileWithLineNum()).Msgf("GORM | "+msg, data...)
+	}
+}
+
+func (gl GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if gl.level() >= gormlogger.Error {
+		gormComponent(ctx).Error(ctx, fmt.Errorf(msg, data...)).Str("caller", utils.FileWithLineNum()).Msg("GORM | Error")
+	}
+}
+
+func (gl GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if gl.level() <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	caller := utils.FileWithLineNum()
+	logger := gormComponent(ctx)
+
+	switch {
+	case err != nil && gl.level() >= gormlogger.Error && (!errors.Is(err, gorm.ErrRecordNotFound) || !gl.IgnoreRecordNotFoundError):
+		sql, rows := fc()
+
+		logger.Error(ctx, err).
+			Dict("extra", sqlLogs(sql, rows, elapsed, caller)).
+			Msg("GORM | Error")
+	case gl.SlowThreshold != 0 && elapsed > gl.SlowThreshold && gl.level() >= gormlogger.Warn:
+		sql, rows := fc()
+
+		logger.Warn(ctx).
+			Dict("extra", sqlLogs(sql, rows, elapsed, caller).
+				Bool("slow_query", true).
+				Float64("slow_threshold_ms", durationMs(gl.SlowThreshold))).
+			Msgf("GORM | Slow SQL >= %v", gl.SlowThreshold)
+	case gl.level() >= gormlogger.Info && logger.Level() <= zerolog.InfoLevel:
+		sql, rows := fc()
+
+		logger.Info(ctx).
+			Dict("extra", sqlLogs(sql, rows, elapsed, caller)).
+			Msg("GORM | SQL")
+	}
+}
+
+// ParamsFilter implements the gorm.ParamsFilter, replacing the bound parameter values when RedactParams is set.
+func (gl GormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if !gl.RedactParams {
+		return sql, params
+	}
+
+	redacted := make([]interface{}, len(params))
+
+	for i := range params {
+		redacted[i] = REDACTED
+	}
+
+	return sql, redacted
+}
+
+func (gl GormLogger) level() gormlogger.LogLevel {
+	if gl.LogLevel == 0 {
+		return gormlogger.Info
+	}
+
+	return gl.LogLevel
+}
+
+func gormComponent(ctx context.Context) *Logger {
+	return FromContext(ctx).Component(ComponentGorm)
+}
+
+func sqlLogs(sql string, rows int64, elapsed time.Duration, caller string) *zerolog.Event {
+	log := zerolog.Dict().
+		Str("sql", sql).
+		Float64("duration_ms", durationMs(elapsed)).
+		Str("caller", caller)
+
+	if rows != -1 {
+		log.Int64("rows_affected", rows)
+	}
+
+	return log
+}
+
+func durationMs(duration time.Duration) float64 {
+	return float64(duration.Nanoseconds()) / 1e6
+}
@@ -0,0 +1,103 @@
+package liberlogger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestGormLogger_Trace(t *testing.T) {
+	tests := []struct {
+		name      string
+		gl        GormLogger
+		elapsed   time.Duration
+		err       error
+		wantLevel string
+		wantMsg   string
+		wantSlow  bool
+	}{
+		{
+			name:      "Should log a query as info",
+			gl:        NewGormLogger(),
+			wantLevel: "info",
+			wantMsg:   "GORM | SQL",
+		},
+		{
+			name:      "Should log a slow query as warn",
+			gl:        NewGormLogger(),
+			elapsed:   300 * time.Millisecond,
+			wantLevel: "warn",
+			wantMsg:   "GORM | Slow SQL >= 200ms",
+			wantSlow:  true,
+		},
+		{
+			name:      "Should log a failed query as error",
+			gl:        NewGormLogger(),
+			err:       errors.New("duplicate key"),
+			wantLevel: "error",
+			wantMsg:   "GORM | Error",
+		},
+		{
+			name: "Should not log a record not found when ignored",
+			gl:   GormLogger{IgnoreRecordNotFoundError: true, LogLevel: gormlogger.Error},
+			err:  gorm.ErrRecordNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			ctx := New(WithOutput(&out)).WithContext(context.Background())
+
+			tt.gl.Trace(ctx, time.Now().Add(-tt.elapsed), func() (string, int64) {
+				return `SELECT * FROM "users" WHERE id = 1`, 1
+			}, tt.err)
+
+			if tt.wantLevel == "" {
+				if out.Len() > 0 {
+					t.Errorf("log = %s, want nothing", out.String())
+				}
+				return
+			}
+
+			var got struct {
+				Level     string
+				Message   string
+				Component string
+				Extra     map[string]interface{}
+			}
+			if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+				t.Fatalf("invalid log %q: %v", out.String(), err)
+			}
+
+			if got.Level != tt.wantLevel || got.Message != tt.wantMsg || got.Component != ComponentGorm {
+				t.Errorf("log = %+v, want level %s, message %s and component gorm", got, tt.wantLevel, tt.wantMsg)
+			}
+
+			if got.Extra["sql"] != `SELECT * FROM "users" WHERE id = 1` || got.Extra["rows_affected"] != float64(1) {
+				t.Errorf("extra = %+v, want the sql and rows affected", got.Extra)
+			}
+
+			if slow, _ := got.Extra["slow_query"].(bool); slow != tt.wantSlow {
+				t.Errorf("slow_query = %v, want %v", slow, tt.wantSlow)
+			}
+		})
+	}
+}
+
+func TestGormLogger_ParamsFilter(t *testing.T) {
+	gl := GormLogger{RedactParams: true}
+
+	_, got := gl.ParamsFilter(context.Background(), "SELECT * FROM users WHERE document = ?", "58707647000")
+
+	if want := []interface{}{REDACTED}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParamsFilter() = %v, want %v", got, want)
+	}
+}
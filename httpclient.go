@@ -1,10 +1,12 @@
 package liberlogger
 
 import (
-	"bytes"
-	"context"
-	"io"
 	"net/http"
+	"time"
+
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
 )
 
 // This type implements the http.RoundTripper interface
@@ -12,72 +14,151 @@ type HttpClient struct {
 	Proxied      http.RoundTripper
 	RedactedKeys []string
 	Maskedkeys   []string
+	// Redactor is used instead of the RedactedKeys and Maskedkeys when set.
+	Redactor *Redactor
+	// DisableSpan stops the child span, and the trace headers, created when the request context has a
+	// Data Dog span. Useful when the client is already traced, as by tracing.HttpTrace.
+	DisableSpan bool
+	// MaxRequestBodyBytes and MaxResponseBodyBytes limit the logged bytes of the bodies. Zero uses the limits
+	// of SetMaxBodyBytes and a negative value logs the bodies whole.
+	MaxRequestBodyBytes  int
+	MaxResponseBodyBytes int
+	// Levels sets the level of the response logs, StatusLevel when nil. SlowThreshold promotes to warn the
+	// responses taking the threshold or longer.
+	Levels        LevelMapper
+	SlowThreshold time.Duration
+}
+
+// WithRequestLogger returns a shallow copy of req whose HttpClient logs are written by logger,
+// as logger.With(map[string]interface{}{"partner": "bank"}).
+func WithRequestLogger(req *http.Request, logger *Logger) *http.Request {
+	return req.WithContext(logger.WithContext(req.Context()))
+}
+
+// WithRequestFields returns a shallow copy of req whose HttpClient logs carry fields.
+func WithRequestFields(req *http.Request, fields map[string]interface{}) *http.Request {
+	return req.WithContext(WithFields(req.Context(), fields))
+}
+
+func (hc HttpClient) redactor() *Redactor {
+	if hc.Redactor != nil {
+		return hc.Redactor
+	}
+
+	return keysRedactor(hc.RedactedKeys, hc.Maskedkeys)
+}
+
+func (hc HttpClient) redact(body interface{}) interface{} {
+	return hc.redactor().Redact(body)
 }
 
 func (hc HttpClient) getRequestBody(req *http.Request) any {
-	var bodyRequest map[string]any
+	limit := bodyLimit(hc.MaxRequestBodyBytes, &maxRequestBodyBytes)
 
-	err := extractBody(req, &bodyRequest)
+	bodyRequest, err := extractBody(req, limit)
 
 	if err != nil {
-		bodyBytes, _ := io.ReadAll(req.Body)
-		defer req.Body.Close()
+		if req.Body == nil {
+			return nil
+		}
 
-		req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+		var bodyBytes []byte
+		bodyBytes, req.Body, _, _ = readBody(req.Body, limit)
 
-		return string(bodyBytes)
+		return hc.redact(string(bodyBytes))
 	}
 
-	return Redact(hc.RedactedKeys, hc.Maskedkeys, bodyRequest)
+	return hc.redact(bodyRequest)
 }
 
 func (hc HttpClient) getResponseBody(res *http.Response) any {
-	var bodyResponse map[string]any
+	limit := bodyLimit(hc.MaxResponseBodyBytes, &maxResponseBodyBytes)
 
-	err := extractBody(res, &bodyResponse)
+	bodyResponse, err := extractBody(res, limit)
 
 	if err != nil {
-		bodyBytes, _ := io.ReadAll(res.Body)
-
-		defer res.Body.Close()
+		if res.Body == nil {
+			return nil
+		}
 
-		res.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+		var bodyBytes []byte
+		bodyBytes, res.Body, _, _ = readBody(res.Body, limit)
 
-		return string(bodyBytes)
+		return hc.redact(string(bodyBytes))
 	}
 
-	return Redact(hc.RedactedKeys, hc.Maskedkeys, bodyResponse)
+	return hc.redact(bodyResponse)
 }
 
 func (hc HttpClient) RoundTrip(req *http.Request) (res *http.Response, err error) {
-	ctx := context.TODO()
+	ctx := req.Context()
+
+	if _, ok := tracer.SpanFromContext(ctx); ok && !hc.DisableSpan {
+		var span ddtrace.Span
+
+		span, ctx = tracer.StartSpanFromContext(ctx, "http.request",
+			tracer.SpanType(ext.SpanTypeHTTP),
+			// A fixed resource name, as the paths can carry documents and ids and are unbounded.
+			tracer.ResourceName("http.request"),
+			tracer.Tag(ext.SpanKind, ext.SpanKindClient),
+			tracer.Tag(ext.HTTPMethod, req.Method),
+			tracer.Tag(ext.HTTPURL, hc.redactor().RedactURL(req.URL)),
+		)
+
+		defer func() {
+			if res != nil {
+				span.SetTag(ext.HTTPCode, res.StatusCode)
+			}
+
+			span.Finish(tracer.WithError(err))
+		}()
+
+		ctx = WithFields(ctx, map[string]interface{}{
+			"dd.span_id":  span.Context().SpanID(),
+			"dd.trace_id": span.Context().TraceID(),
+		})
+
+		req = req.Clone(ctx)
+
+		if err := tracer.Inject(span.Context(), tracer.HTTPHeadersCarrier(req.Header)); err != nil {
+			FromContext(ctx).Component(ComponentHttpClient).Warn(ctx).
+				Str("error", err.Error()).
+				Msg("HTTP Client | Error when inject trace headers in liberlogger")
+		}
+	}
+
+	logger := FromContext(ctx).Component(ComponentHttpClient)
 
 	requestBody := hc.getRequestBody(req)
 
-	Info(ctx).
-		Interface("headers", Redact(hc.RedactedKeys, hc.Maskedkeys, parseHeaders(req.Header))).
+	logger.Info(ctx).
+		Interface("headers", hc.redact(parseHeaders(req.Header))).
 		Interface("body", requestBody).
-		Dict("extra", extraLogs(req, nil)).
-		Msg(formatFinalMsg(req, "HTTP Client"))
+		Dict("extra", extraLogs(req, nil, hc.redactor())).
+		Msg(formatFinalMsg(req, "HTTP Client", hc.redactor()))
+
+	start := time.Now()
 
 	res, err = hc.Proxied.RoundTrip(req)
 
+	duration := time.Since(start)
+
 	if err != nil {
-		Error(ctx, err).
-			Interface("headers", Redact(hc.RedactedKeys, hc.Maskedkeys, parseHeaders(req.Header))).
-			Dict("extra", extraLogs(req, err)).
-			Msg(formatFinalMsg(req, "HTTP Client"))
+		logger.levelEvent(ctx, responseLevel(hc.Levels, hc.SlowThreshold, 0, err, duration), err).
+			Interface("headers", hc.redact(parseHeaders(req.Header))).
+			Dict("extra", withDuration(extraLogs(req, err, hc.redactor()), duration)).
+			Msg(formatFinalMsg(req, "HTTP Client", hc.redactor()))
 
 		return
 	}
 
 	responseBody := hc.getResponseBody(res)
 
-	Info(ctx).
-		Interface("headers", Redact(hc.RedactedKeys, hc.Maskedkeys, parseHeaders(res.Header))).
+	logger.levelEvent(ctx, responseLevel(hc.Levels, hc.SlowThreshold, res.StatusCode, nil, duration), nil).
+		Interface("headers", hc.redact(parseHeaders(res.Header))).
 		Interface("body", responseBody).
-		Dict("extra", extraLogs(res, nil)).
-		Msg(formatFinalMsg(res, "HTTP Client"))
+		Dict("extra", withDuration(extraLogs(res, nil, hc.redactor()), duration)).
+		Msg(formatFinalMsg(res, "HTTP Client", hc.redactor()))
 
 	return
 }
@@ -0,0 +1,95 @@
+package liberlogger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/mocktracer"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestHttpClient_RoundTrip(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	var out bytes.Buffer
+	var traceHeader string
+
+	client := &http.Client{
+		Transport: HttpClient{
+			Proxied: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				traceHeader = req.Header.Get("X-Datadog-Trace-Id")
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(`{"access_token":"secret"}`)),
+					Request:    req,
+				}, nil
+			}),
+			RedactedKeys: []string{"access_token"},
+		},
+	}
+
+	span, ctx := tracer.StartSpanFromContext(New(WithOutput(&out)).WithContext(context.Background()), "parent")
+	defer span.Finish()
+
+	req, _ := http.NewRequestWithContext(WithField(ctx, "log_id", "123"), http.MethodGet, "https://partner.com/token", nil)
+	req = WithRequestFields(req, map[string]interface{}{"partner": "bank"})
+
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if traceHeader == "" {
+		t.Error("X-Datadog-Trace-Id header = empty, want the trace id injected")
+	}
+
+	spans := mt.FinishedSpans()
+	if len(spans) != 1 || spans[0].ParentID() != span.Context().SpanID() {
+		t.Fatalf("finished spans = %v, want a child span of the request context span", spans)
+	}
+
+	if resource := spans[0].Tag(ext.ResourceName); resource != "http.request" {
+		t.Errorf("resource name = %v, want http.request and not the path", resource)
+	}
+
+	scanner := bufio.NewScanner(&out)
+	lines := 0
+
+	for scanner.Scan() {
+		lines++
+
+		var log map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &log); err != nil {
+			t.Fatalf("invalid log %q: %v", scanner.Text(), err)
+		}
+
+		if log["log_id"] != "123" || log["partner"] != "bank" || log["component"] != ComponentHttpClient {
+			t.Errorf("log = %v, want the fields of the request context", log)
+		}
+
+		if log["dd.trace_id"] != float64(span.Context().TraceID()) {
+			t.Errorf("dd.trace_id = %v, want %v", log["dd.trace_id"], span.Context().TraceID())
+		}
+	}
+
+	if lines != 2 {
+		t.Errorf("logs = %d, want the request and the response", lines)
+	}
+}
@@ -0,0 +1,152 @@
+package liberlogger
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Field names of the "extra" object of the HTTP logs, written by the middlewares and the HttpClient.
+// Dashboards can depend on them; see the README for the schema.
+const (
+	FieldURL                   = "url"
+	FieldMethod                = "method"
+	FieldHost                  = "host"
+	FieldProto                 = "proto"
+	FieldUserAgent             = "user_agent"
+	FieldRemoteAddr            = "remote_addr"
+	FieldRequestContentLength  = "request_content_length"
+	FieldResponseContentLength = "response_content_length"
+	FieldStatus                = "status"
+	FieldRoute                 = "route"
+	FieldDuration              = "duration_ms"
+	FieldError                 = "error"
+	FieldRequest               = "request"
+)
+
+var trustedProxies atomic.Pointer[[]*net.IPNet]
+
+// SetTrustedProxies sets the IPs and CIDRs of the proxies trusted to set the X-Forwarded-For header,
+// used to resolve the remote_addr of the HTTP logs.
+func SetTrustedProxies(proxies []string) error {
+	networks := make([]*net.IPNet, 0, len(proxies))
+
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return err
+		}
+
+		networks = append(networks, network)
+	}
+
+	trustedProxies.Store(&networks)
+
+	return nil
+}
+
+func isTrustedProxy(addr string) bool {
+	networks := trustedProxies.Load()
+	if networks == nil {
+		return false
+	}
+
+	ip := net.ParseIP(strings.TrimSpace(addr))
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range *networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// remoteAddr returns the client IP of the request. The X-Forwarded-For header is only honoured when the
+// request comes from a trusted proxy, taking the last address that is not a trusted proxy.
+func remoteAddr(request *http.Request) string {
+	addr := request.RemoteAddr
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+
+	if !isTrustedProxy(addr) {
+		return addr
+	}
+
+	forwarded := strings.Split(strings.Join(request.Header.Values("X-Forwarded-For"), ","), ",")
+
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		client := strings.TrimSpace(forwarded[i])
+		if client == "" {
+			continue
+		}
+
+		addr = client
+
+		if !isTrustedProxy(client) {
+			break
+		}
+	}
+
+	return addr
+}
+
+func requestFields(log *zerolog.Event, request *http.Request, redactor *Redactor) *zerolog.Event {
+	log.
+		Str(FieldURL, redactedURL(request, redactor)).
+		Str(FieldMethod, request.Method).
+		Str(FieldHost, requestHost(request)).
+		Str(FieldProto, request.Proto)
+
+	if userAgent := request.UserAgent(); userAgent != "" {
+		log.Str(FieldUserAgent, userAgent)
+	}
+
+	if request.RemoteAddr != "" {
+		log.Str(FieldRemoteAddr, remoteAddr(request))
+	}
+
+	if request.ContentLength >= 0 {
+		log.Int64(FieldRequestContentLength, request.ContentLength)
+	}
+
+	return log
+}
+
+// redactedURL returns the URL of the request redacted by the redactor, or only without the userinfo when nil.
+func redactedURL(request *http.Request, redactor *Redactor) string {
+	if redactor == nil {
+		redactor = keysRedactor(nil, nil)
+	}
+
+	return redactor.RedactURL(request.URL)
+}
+
+func requestHost(request *http.Request) string {
+	if request.Host != "" {
+		return request.Host
+	}
+
+	return request.URL.Host
+}
+
+// withDuration adds the duration, in milliseconds, to the extra logs of a response.
+func withDuration(log *zerolog.Event, duration time.Duration) *zerolog.Event {
+	return log.Float64(FieldDuration, durationMs(duration))
+}
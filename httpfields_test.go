@@ -0,0 +1,57 @@
+package liberlogger
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_remoteAddr(t *testing.T) {
+	if err := SetTrustedProxies([]string{"10.0.0.0/8", "192.168.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	defer SetTrustedProxies(nil)
+
+	tests := []struct {
+		name          string
+		remoteAddr    string
+		xForwardedFor string
+		want          string
+	}{
+		{
+			name:       "Should return the remote address without X-Forwarded-For",
+			remoteAddr: "200.100.10.1:54321",
+			want:       "200.100.10.1",
+		},
+		{
+			name:          "Should ignore X-Forwarded-For from an untrusted address",
+			remoteAddr:    "200.100.10.1:54321",
+			xForwardedFor: "1.2.3.4",
+			want:          "200.100.10.1",
+		},
+		{
+			name:          "Should honour X-Forwarded-For from a trusted proxy",
+			remoteAddr:    "10.0.0.5:54321",
+			xForwardedFor: "1.2.3.4",
+			want:          "1.2.3.4",
+		},
+		{
+			name:          "Should skip the trusted proxies of X-Forwarded-For",
+			remoteAddr:    "10.0.0.5:54321",
+			xForwardedFor: "6.6.6.6, 1.2.3.4, 192.168.0.1",
+			want:          "1.2.3.4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.xForwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.xForwardedFor)
+			}
+
+			if got := remoteAddr(req); got != tt.want {
+				t.Errorf("remoteAddr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
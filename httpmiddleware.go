@@ -0,0 +1,246 @@
+package liberlogger
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+)
+
+// MiddlewareOption configures the HTTP server middlewares.
+type MiddlewareOption func(*middleware)
+
+// handlerFunc serves the request behind a middleware, returning the route template matched by the router,
+// empty when unknown, and the handler error.
+type handlerFunc func(w http.ResponseWriter, r *http.Request) (route string, err error)
+
+type middleware struct {
+	redactor        *Redactor
+	routes          RouteRules
+	maxRequestBody  int
+	maxResponseBody int
+	level           LevelMapper
+	slowThreshold   time.Duration
+	component       string
+	route           func(r *http.Request) string
+	errorStatus     func(status int, err error) int // The status responded for a handler error, set by the adapters.
+}
+
+// MiddlewareRedactor sets the Redactor of the headers, bodies and URLs. Defaults to the DefaultKeys
+// redacted and the DefaultKeysToMask masked.
+func MiddlewareRedactor(redactor *Redactor) MiddlewareOption {
+	return func(m *middleware) {
+		m.redactor = redactor
+	}
+}
+
+// MiddlewareRedactKeys sets the keys redacted and masked, replacing the defaults.
+func MiddlewareRedactKeys(redactKeys []string, maskKeys []string) MiddlewareOption {
+	return func(m *middleware) {
+		m.redactor = keysRedactor(redactKeys, maskKeys)
+	}
+}
+
+// MiddlewareIgnoreRoutes stops logging the requests of the paths, ignoring the query and the trailing slash.
+func MiddlewareIgnoreRoutes(routes ...string) MiddlewareOption {
+	return func(m *middleware) {
+		m.routes.rules = append(m.routes.rules, ignoreRouteRules(routes)...)
+	}
+}
+
+// MiddlewareRouteRules sets how the requests of the routes are logged. The rules are matched after the
+// ones of the previous options, the first matching a request applies.
+func MiddlewareRouteRules(rules *RouteRules) MiddlewareOption {
+	return func(m *middleware) {
+		if rules != nil {
+			m.routes.rules = append(m.routes.rules, rules.rules...)
+		}
+	}
+}
+
+// MiddlewareBodyLimits sets the limits of the logged bytes of the bodies, as the HttpClient fields
+// MaxRequestBodyBytes and MaxResponseBodyBytes.
+func MiddlewareBodyLimits(request int, response int) MiddlewareOption {
+	return func(m *middleware) {
+		m.maxRequestBody = request
+		m.maxResponseBody = response
+	}
+}
+
+// MiddlewareLevels sets the level of the response logs. Defaults to StatusLevel.
+func MiddlewareLevels(level LevelMapper) MiddlewareOption {
+	return func(m *middleware) {
+		m.level = level
+	}
+}
+
+// MiddlewareSlowThreshold promotes to warn the response logs of the requests taking the threshold or longer.
+func MiddlewareSlowThreshold(threshold time.Duration) MiddlewareOption {
+	return func(m *middleware) {
+		m.slowThreshold = threshold
+	}
+}
+
+// MiddlewareComponent sets the component of the logs. Defaults to ComponentHTTP.
+func MiddlewareComponent(name string) MiddlewareOption {
+	return func(m *middleware) {
+		m.component = name
+	}
+}
+
+// MiddlewareRoute sets the resolver of the route template of the requests, as /users/{id}, logged as the
+// route field and set as the resource name of the Datadog span of the request. The adapters of the
+// routers resolve it from their routes.
+func MiddlewareRoute(route func(r *http.Request) string) MiddlewareOption {
+	return func(m *middleware) {
+		m.route = route
+	}
+}
+
+func newMiddleware(opts []MiddlewareOption) *middleware {
+	m := &middleware{
+		redactor:    keysRedactor(DefaultKeys, DefaultKeysToMask),
+		level:       StatusLevel,
+		component:   ComponentHTTP,
+		errorStatus: func(status int, err error) int { return http.StatusInternalServerError },
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// HTTPMiddleware logs the requests and responses of any net/http router, as Gorilla Mux or the standard
+// http.ServeMux, with their redacted headers, bodies and URLs.
+func HTTPMiddleware(opts ...MiddlewareOption) func(next http.Handler) http.Handler {
+	m := newMiddleware(opts)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = m.serve(w, r, func(w http.ResponseWriter, r *http.Request) (string, error) {
+				next.ServeHTTP(w, r)
+				return m.resolveRoute(r), nil
+			})
+		})
+	}
+}
+
+// serve logs the request, calls next with the LogResponseWriter and logs the response, returning the
+// error of next.
+func (m *middleware) serve(w http.ResponseWriter, r *http.Request, next handlerFunc) error {
+	levels, slowThreshold := m.level, m.slowThreshold
+
+	action := RouteLog
+	if rule, ok := m.routes.match(r); ok {
+		action = rule.Action
+
+		if action == RouteSkip || action == RouteSample && !rule.sampled() {
+			_, err := next(w, r)
+			return err
+		}
+
+		if rule.Levels != nil {
+			levels = rule.Levels
+		}
+
+		if rule.SlowThreshold > 0 {
+			slowThreshold = rule.SlowThreshold
+		}
+	}
+
+	start := time.Now()
+
+	ctx := r.Context()
+	logger := FromContext(ctx).Component(m.component)
+
+	m.logRequest(ctx, logger, r, action)
+
+	logRespWriter := NewLogResponseWriter(w, r)
+	logRespWriter.limit = bodyLimit(m.maxResponseBody, &maxResponseBodyBytes)
+
+	if action == RouteHeadersOnly || action == RouteNoBody {
+		logRespWriter.limit = 0
+	}
+
+	route, err := next(logRespWriter, r)
+
+	duration := time.Since(start)
+
+	if logRespWriter.StatusCode == 0 {
+		logRespWriter.StatusCode = http.StatusOK
+	}
+
+	if err != nil {
+		logRespWriter.StatusCode = m.errorStatus(logRespWriter.StatusCode, err)
+	}
+
+	extra := withDuration(extraLogs(logRespWriter, err, m.redactor), duration)
+
+	if route != "" {
+		extra.Str(FieldRoute, route)
+
+		if span, ok := tracer.SpanFromContext(ctx); ok {
+			span.SetTag(ext.ResourceName, r.Method+" "+route)
+		}
+	}
+
+	level := responseLevel(levels, slowThreshold, logRespWriter.StatusCode, err, duration)
+
+	event := logger.levelEvent(ctx, level, err).
+		Interface("headers", m.redactor.Redact(parseHeaders(logRespWriter.Header())))
+
+	switch {
+	case err != nil, action == RouteHeadersOnly:
+	case action == RouteNoBody:
+		event.Interface("body", bodyMetadata(logRespWriter.Header(), logRespWriter.size))
+	default:
+		event.Interface("body", m.redactor.Redact(logRespWriter.decodedBody()))
+	}
+
+	event.
+		Dict("extra", extra).
+		Msg(formatFinalMsg(logRespWriter, "HTTP Server - Response |", m.redactor))
+
+	return err
+}
+
+func (m *middleware) resolveRoute(r *http.Request) string {
+	if m.route == nil {
+		return ""
+	}
+
+	return m.route(r)
+}
+
+func (m *middleware) logRequest(ctx context.Context, logger *Logger, r *http.Request, action RouteAction) {
+	var body interface{}
+	var err error
+
+	switch action {
+	case RouteHeadersOnly:
+	case RouteNoBody:
+		body = bodyMetadata(r.Header, r.ContentLength)
+	default:
+		body, err = extractBody(r, bodyLimit(m.maxRequestBody, &maxRequestBodyBytes))
+		body = m.redactor.Redact(body)
+	}
+
+	event, msg := logger.Info(ctx), "HTTP Server - Request |"
+	if err != nil {
+		event, msg = logger.Error(ctx, err), "HTTP Server - Request | Error when parse in liberlogger"
+	}
+
+	event.Interface("headers", m.redactor.Redact(parseHeaders(r.Header)))
+
+	if action != RouteHeadersOnly {
+		event.Interface("body", body)
+	}
+
+	event.
+		Dict("extra", extraLogs(r, err, m.redactor)).
+		Msg(formatFinalMsg(r, msg, m.redactor))
+}
@@ -0,0 +1,505 @@
+package liberlogger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-chi/chi/v5"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/gorilla/mux"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/mocktracer"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+)
+
+// middlewareFramework serves the handler on /users/{id} and /health behind the middleware of a framework,
+// logging with logger.
+type middlewareFramework struct {
+	name      string
+	component string
+	route     string
+	serve     func(logger *Logger, routesIgnore []string, handler http.HandlerFunc) http.Handler
+}
+
+func withLogger(logger *Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(logger.WithContext(r.Context())))
+	})
+}
+
+var middlewareFrameworks = []middlewareFramework{
+	{
+		name:      "net/http",
+		component: ComponentHTTP,
+		serve: func(logger *Logger, routesIgnore []string, handler http.HandlerFunc) http.Handler {
+			mux := http.NewServeMux()
+			mux.Handle("/users/{id}", handler)
+			mux.Handle("/health", handler)
+
+			return withLogger(logger, HTTPMiddleware(MiddlewareIgnoreRoutes(routesIgnore...))(mux))
+		},
+	},
+	{
+		name:      "gorilla/mux",
+		component: ComponentGorillaMux,
+		route:     "/users/{id}",
+		serve: func(logger *Logger, routesIgnore []string, handler http.HandlerFunc) http.Handler {
+			router := mux.NewRouter()
+			router.Use(GorillaMux(routesIgnore))
+			router.HandleFunc("/users/{id}", handler)
+			router.HandleFunc("/health", handler)
+
+			return withLogger(logger, router)
+		},
+	},
+	{
+		name:      "echo/v4",
+		component: ComponentEcho,
+		route:     "/users/:id",
+		serve: func(logger *Logger, routesIgnore []string, handler http.HandlerFunc) http.Handler {
+			e := echo.New()
+			e.Use(EchoV4(routesIgnore))
+			e.Any("/users/:id", echo.WrapHandler(handler))
+			e.Any("/health", echo.WrapHandler(handler))
+
+			return withLogger(logger, e)
+		},
+	},
+	{
+		name:      "chi",
+		component: ComponentChi,
+		route:     "/users/{id}",
+		serve: func(logger *Logger, routesIgnore []string, handler http.HandlerFunc) http.Handler {
+			router := chi.NewRouter()
+			router.Use(Chi(MiddlewareIgnoreRoutes(routesIgnore...)))
+			router.HandleFunc("/users/{id}", handler)
+			router.HandleFunc("/health", handler)
+
+			return withLogger(logger, router)
+		},
+	},
+	{
+		name:      "gin",
+		component: ComponentGin,
+		route:     "/users/:id",
+		serve: func(logger *Logger, routesIgnore []string, handler http.HandlerFunc) http.Handler {
+			gin.SetMode(gin.TestMode)
+
+			engine := gin.New()
+			engine.Use(Gin(MiddlewareIgnoreRoutes(routesIgnore...)))
+			engine.Any("/users/:id", gin.WrapF(handler))
+			engine.Any("/health", gin.WrapF(handler))
+
+			return withLogger(logger, engine)
+		},
+	},
+	{
+		name:      "fiber",
+		component: ComponentFiber,
+		route:     "/users/:id",
+		serve: func(logger *Logger, routesIgnore []string, handler http.HandlerFunc) http.Handler {
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.SetUserContext(logger.WithContext(c.UserContext()))
+				return c.Next()
+			})
+			app.Use(Fiber(MiddlewareIgnoreRoutes(routesIgnore...)))
+			app.All("/users/:id", adaptor.HTTPHandlerFunc(handler))
+			app.All("/health", adaptor.HTTPHandlerFunc(handler))
+
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				resp, err := app.Test(r, -1)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				defer resp.Body.Close()
+
+				w.WriteHeader(resp.StatusCode)
+				_, _ = io.Copy(w, resp.Body)
+			})
+		},
+	},
+}
+
+func readLogs(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	var logs []map[string]interface{}
+
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		var log map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &log); err != nil {
+			t.Fatalf("invalid log %q: %v", scanner.Text(), err)
+		}
+		logs = append(logs, log)
+	}
+
+	return logs
+}
+
+func TestMiddlewareFrameworks(t *testing.T) {
+	tests := []struct {
+		name         string
+		target       string
+		status       int
+		wantLogs     int
+		wantResponse string
+	}{
+		{
+			name:         "Should log the request and the response with the status",
+			target:       "/users/1?password=s3cr3t",
+			status:       http.StatusCreated,
+			wantLogs:     2,
+			wantResponse: "HTTP Server - Response | POST 201 /users/1?password=" + REDACTED,
+		},
+		{
+			name:         "Should log the status 200 when the handler does not write the header",
+			target:       "/users/1",
+			wantLogs:     2,
+			wantResponse: "HTTP Server - Response | POST 200 /users/1",
+		},
+		{
+			name:     "Should not log the ignored routes",
+			target:   "/health",
+			status:   http.StatusOK,
+			wantLogs: 0,
+		},
+		{
+			name:     "Should not log the ignored routes with a query",
+			target:   "/health?full=1",
+			status:   http.StatusOK,
+			wantLogs: 0,
+		},
+	}
+	for _, framework := range middlewareFrameworks {
+		for _, tt := range tests {
+			t.Run(framework.name+"/"+tt.name, func(t *testing.T) {
+				var out bytes.Buffer
+
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "application/json")
+					if tt.status != 0 {
+						w.WriteHeader(tt.status)
+					}
+					_, _ = w.Write([]byte(`{"id":"1","access_token":"s3cr3t","cpf":"12345678909"}`))
+				}
+
+				server := framework.serve(New(WithOutput(&out)), []string{"/health"}, handler)
+
+				req := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(`{"password":"s3cr3t","client_secret":"s3cr3t"}`))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer s3cr3t")
+				server.ServeHTTP(httptest.NewRecorder(), req)
+
+				if strings.Contains(out.String(), "s3cr3t") || strings.Contains(out.String(), "12345678909") {
+					t.Errorf("logs = %s, want the DefaultKeys redacted and the DefaultKeysToMask masked", out.String())
+				}
+
+				logs := readLogs(t, &out)
+				if len(logs) != tt.wantLogs {
+					t.Fatalf("logs = %v, want %d logs", logs, tt.wantLogs)
+				}
+
+				if tt.wantLogs == 0 {
+					return
+				}
+
+				for _, log := range logs {
+					if log["component"] != framework.component {
+						t.Errorf("component = %v, want %v", log["component"], framework.component)
+					}
+				}
+
+				if logs[1]["message"] != tt.wantResponse {
+					t.Errorf("response message = %v, want %v", logs[1]["message"], tt.wantResponse)
+				}
+
+				if route, _ := logs[1]["extra"].(map[string]interface{})[FieldRoute].(string); route != framework.route {
+					t.Errorf("route = %q, want %q", route, framework.route)
+				}
+			})
+		}
+	}
+}
+
+func TestHTTPMiddlewareOptions(t *testing.T) {
+	maskName, err := NewRedactor(MaskRules("name"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		opts         []MiddlewareOption
+		wantLevel    string
+		wantRespBody interface{}
+	}{
+		{
+			name:         "Should redact the keys of MiddlewareRedactKeys",
+			opts:         []MiddlewareOption{MiddlewareRedactKeys([]string{"id"}, nil)},
+			wantLevel:    "info",
+			wantRespBody: map[string]interface{}{"id": REDACTED, "name": "John"},
+		},
+		{
+			name:         "Should redact with the Redactor of MiddlewareRedactor",
+			opts:         []MiddlewareOption{MiddlewareRedactor(maskName)},
+			wantLevel:    "info",
+			wantRespBody: map[string]interface{}{"id": "1", "name": "Jo**"},
+		},
+		{
+			name:      "Should truncate the response body to the limit of MiddlewareBodyLimits",
+			opts:      []MiddlewareOption{MiddlewareBodyLimits(-1, 8)},
+			wantLevel: "info",
+			wantRespBody: map[string]interface{}{
+				"truncated": true,
+				"size":      float64(len(`{"id":"1","name":"John"}`)),
+				"body":      `{"id":`,
+			},
+		},
+		{
+			name: "Should log the response with the level of MiddlewareLevels",
+			opts: []MiddlewareOption{MiddlewareLevels(func(status int, err error) zerolog.Level {
+				if status >= http.StatusBadRequest {
+					return zerolog.WarnLevel
+				}
+				return zerolog.InfoLevel
+			})},
+			wantLevel:    "warn",
+			wantRespBody: map[string]interface{}{"id": "1", "name": "John"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			status := http.StatusOK
+			if tt.wantLevel == "warn" {
+				status = http.StatusNotFound
+			}
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(`{"id":"1","name":"John"}`))
+			})
+
+			server := withLogger(New(WithOutput(&out)), HTTPMiddleware(tt.opts...)(handler))
+			server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+
+			logs := readLogs(t, &out)
+			if len(logs) != 2 {
+				t.Fatalf("logs = %v, want the request and the response", logs)
+			}
+
+			response := logs[1]
+			if response["level"] != tt.wantLevel {
+				t.Errorf("response level = %v, want %v", response["level"], tt.wantLevel)
+			}
+
+			got, _ := json.Marshal(response["body"])
+			want, _ := json.Marshal(tt.wantRespBody)
+			if !bytes.Equal(got, want) {
+				t.Errorf("response body = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestMiddlewareHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		serve      func(logger *Logger) http.Handler
+		wantLevel  string
+		wantStatus float64
+	}{
+		{
+			name: "Should log the error of c.AbortWithError of gin with its status",
+			serve: func(logger *Logger) http.Handler {
+				gin.SetMode(gin.TestMode)
+
+				engine := gin.New()
+				engine.Use(Gin())
+				engine.GET("/users", func(c *gin.Context) {
+					_ = c.AbortWithError(http.StatusBadRequest, errors.New("invalid user"))
+				})
+
+				return withLogger(logger, engine)
+			},
+			wantLevel:  "warn",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Should log the error of fiber with the status of the *fiber.Error",
+			serve: func(logger *Logger) http.Handler {
+				app := fiber.New()
+				app.Use(func(c *fiber.Ctx) error {
+					c.SetUserContext(logger.WithContext(c.UserContext()))
+					return c.Next()
+				})
+				app.Use(Fiber())
+				app.Get("/users", func(c *fiber.Ctx) error {
+					return fiber.NewError(http.StatusNotFound, "invalid user")
+				})
+
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if _, err := app.Test(r, -1); err != nil {
+						t.Fatal(err)
+					}
+				})
+			},
+			wantLevel:  "warn",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "Should log the error of fiber without a *fiber.Error as internal server error",
+			serve: func(logger *Logger) http.Handler {
+				app := fiber.New()
+				app.Use(func(c *fiber.Ctx) error {
+					c.SetUserContext(logger.WithContext(c.UserContext()))
+					return c.Next()
+				})
+				app.Use(Fiber())
+				app.Get("/users", func(c *fiber.Ctx) error {
+					return errors.New("invalid user")
+				})
+
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if _, err := app.Test(r, -1); err != nil {
+						t.Fatal(err)
+					}
+				})
+			},
+			wantLevel:  "error",
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			tt.serve(New(WithOutput(&out))).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+
+			logs := readLogs(t, &out)
+			if len(logs) != 2 {
+				t.Fatalf("logs = %v, want the request and the response", logs)
+			}
+
+			response := logs[1]
+			extra := response["extra"].(map[string]interface{})
+
+			if response["level"] != tt.wantLevel || extra["status"] != tt.wantStatus || extra[FieldError] != "invalid user" {
+				t.Errorf("response = %v, want the error invalid user at %v with the status %v", response, tt.wantLevel, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestChiSpanResourceName(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	logger := New(WithOutput(io.Discard))
+
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			span, ctx := tracer.StartSpanFromContext(logger.WithContext(r.Context()), "http.request")
+			defer span.Finish()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	router.Use(Chi())
+	router.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	spans := mt.FinishedSpans()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %v, want the span of the request", spans)
+	}
+
+	if resource := spans[0].Tag(ext.ResourceName); resource != "GET /users/{id}" {
+		t.Errorf("resource name = %v, want GET /users/{id}", resource)
+	}
+}
+
+func TestHTTPMiddlewareResponseWriter(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "Should flush with the http.Flusher",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("data: 1\n\n"))
+				w.(http.Flusher).Flush()
+			},
+		},
+		{
+			name: "Should flush with the http.ResponseController",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("data: 1\n\n"))
+				if err := http.NewResponseController(w).Flush(); err != nil {
+					t.Error(err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			withLogger(New(WithOutput(io.Discard)), HTTPMiddleware()(tt.handler)).
+				ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+			if !rec.Flushed || rec.Body.String() != "data: 1\n\n" {
+				t.Errorf("flushed = %v and body = %q, want the event flushed", rec.Flushed, rec.Body.String())
+			}
+		})
+	}
+
+	t.Run("Should hijack the connection", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, rw, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer conn.Close()
+
+			_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: test\r\n\r\n")
+			_ = rw.Flush()
+		})
+
+		server := httptest.NewServer(withLogger(New(WithOutput(io.Discard)), HTTPMiddleware()(handler)))
+		defer server.Close()
+
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "test")
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+
+		if res.StatusCode != http.StatusSwitchingProtocols {
+			t.Errorf("status = %d, want %d", res.StatusCode, http.StatusSwitchingProtocols)
+		}
+	})
+}
@@ -1,9 +1,13 @@
 package liberlogger
 
 import (
+	"context"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 	"github.com/rs/zerolog/pkgerrors"
 )
 
@@ -15,25 +19,142 @@ const (
 	debugLevel = "debug"
 )
 
+// Init sets the zerolog global level, the stack marshaler and the unix time format, as it always did,
+// leaving the log.Logger set by the service as it is. Until InitWithConfig is called, the package
+// functions, as Info and Error, write through log.Logger.
 func Init(logLevel string) {
-	var zeroLogLevel zerolog.Level
-
 	zerolog.ErrorStackMarshaler = pkgerrors.MarshalStack
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	level := strings.ToLower(logLevel)
 
-	switch level {
+	zerolog.SetGlobalLevel(parseLevel(logLevel))
+}
+
+// InitWithConfig sets up the Default logger, used by Info, Error and the others, from config.
+// It takes over the zerolog log.Logger, replacing the one set by the service, so who uses it directly
+// writes to the same output, at the level of the Default logger, runtime changes included. Use Init to
+// keep log.Logger.
+func InitWithConfig(config Config) {
+	zerolog.ErrorStackMarshaler = pkgerrors.MarshalStack
+	if config.DisableStack {
+		zerolog.ErrorStackMarshaler = nil
+	}
+
+	zerolog.TimeFieldFormat = parseTimeFormat(config.TimeFormat)
+
+	logger := New(WithConfig(config))
+
+	log.Logger = logger.zl.Hook(levelHook{logger: logger})
+
+	SetDefault(logger)
+
+	SetMaxBodyBytes(orDefault(config.MaxRequestBody, DefaultMaxBodyBytes), orDefault(config.MaxResponseBody, DefaultMaxBodyBytes))
+
+	if config.TokenKey != "" {
+		SetTokenKey([]byte(config.TokenKey))
+	}
+
+	if err := SetTrustedProxies(config.TrustedProxies); err != nil {
+		logger.Error(context.Background(), err).Msg("liberlogger | Invalid trusted proxies")
+	}
+
+	env := config.Env
+	if env == "" {
+		env = os.Getenv("ENV")
+	}
+
+	SetRedactBypass(RedactBypass{
+		Policy:           BypassPolicy(config.RedactBypass),
+		Env:              env,
+		AllowedEnvs:      config.BypassEnvs,
+		AlwaysRedactKeys: config.AlwaysRedact,
+	})
+}
+
+// levelHook discards the events of log.Logger below the level of the logger, so the runtime level changes
+// of the Default logger also reach the direct log.* calls.
+type levelHook struct {
+	logger *Logger
+}
+
+func (h levelHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	if level != zerolog.NoLevel && level < h.logger.level.Get() {
+		e.Discard()
+	}
+}
+
+func newZerolog(config Config) zerolog.Logger {
+	writer := config.Writer
+	if writer == nil {
+		writer = os.Stderr
+	}
+
+	if strings.ToLower(config.Format) == FormatConsole {
+		writer = zerolog.ConsoleWriter{Out: writer, TimeFormat: time.RFC3339}
+	}
+
+	zlContext := zerolog.New(writer).With().Timestamp()
+
+	if config.Service != "" {
+		zlContext = zlContext.Str("service", config.Service)
+	}
+
+	if config.Env != "" {
+		zlContext = zlContext.Str("env", config.Env)
+	}
+
+	if config.Version != "" {
+		zlContext = zlContext.Str("version", config.Version)
+	}
+
+	if len(config.Fields) > 0 {
+		zlContext = zlContext.Fields(config.Fields)
+	}
+
+	if config.Caller {
+		zlContext = zlContext.Caller()
+	}
+
+	return zlContext.Logger()
+}
+
+func parseLevel(logLevel string) zerolog.Level {
+	switch strings.ToLower(logLevel) {
 	case fatalLevel:
-		zeroLogLevel = zerolog.FatalLevel
+		return zerolog.FatalLevel
 	case errorLevel:
-		zeroLogLevel = zerolog.ErrorLevel
+		return zerolog.ErrorLevel
 	case warnLevel:
-		zeroLogLevel = zerolog.WarnLevel
+		return zerolog.WarnLevel
 	case debugLevel:
-		zeroLogLevel = zerolog.DebugLevel
+		return zerolog.DebugLevel
+	default:
+		return zerolog.InfoLevel
+	}
+}
+
+func parseTimeFormat(timeFormat string) string {
+	switch strings.ToLower(timeFormat) {
+	case "", "unix":
+		return zerolog.TimeFormatUnix
+	case "unixms":
+		return zerolog.TimeFormatUnixMs
+	case "unixmicro":
+		return zerolog.TimeFormatUnixMicro
+	case "unixnano":
+		return zerolog.TimeFormatUnixNano
+	case "rfc3339":
+		return time.RFC3339
+	case "rfc3339nano":
+		return time.RFC3339Nano
 	default:
-		zeroLogLevel = zerolog.InfoLevel
+		return timeFormat
+	}
+}
+
+func orDefault(value int, defaultValue int) int {
+	if value == 0 {
+		return defaultValue
 	}
 
-	zerolog.SetGlobalLevel(zeroLogLevel)
+	return value
 }
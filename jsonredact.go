@@ -0,0 +1,553 @@
+package liberlogger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+var errInvalidJSON = errors.New("liberlogger: invalid JSON")
+
+// RedactJSON redacts the JSON without unmarshaling it, copying the values not selected by the rules as they
+// are. The result is the same JSON value as Redact of the unmarshaled body, in the original key order.
+func (r *Redactor) RedactJSON(data []byte) ([]byte, error) {
+	var out bytes.Buffer
+	out.Grow(len(data))
+
+	if err := r.newJSONScanner(bytes.NewReader(data), &out).root(); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// RedactJSONStream redacts the JSON read from reader as RedactJSON, writing it to writer as it is read, so
+// only the value being scanned is held in memory. On an error, the JSON written so far is incomplete.
+func (r *Redactor) RedactJSONStream(writer io.Writer, reader io.Reader) error {
+	in, ok := reader.(io.ByteScanner)
+	if !ok {
+		in = bufio.NewReader(reader)
+	}
+
+	out := bufio.NewWriter(writer)
+
+	if err := r.newJSONScanner(in, out).root(); err != nil {
+		return err
+	}
+
+	return out.Flush()
+}
+
+// RedactJSONReader redacts the JSON read from reader as RedactJSONStream, returning the redacted JSON.
+func (r *Redactor) RedactJSONReader(reader io.Reader) ([]byte, error) {
+	var out bytes.Buffer
+
+	if err := r.RedactJSONStream(&out, reader); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// redactRawJSON is the Redact of the raw JSON bodies, nil when empty or invalid.
+func (r *Redactor) redactRawJSON(data []byte) interface{} {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+
+	redacted, err := r.RedactJSON(data)
+	if err != nil {
+		return nil
+	}
+
+	return json.RawMessage(redacted)
+}
+
+// redactJSONPrefix redacts the start of a JSON, returning it up to the last complete value.
+func (r *Redactor) redactJSONPrefix(data []byte) string {
+	var out bytes.Buffer
+	out.Grow(len(data))
+
+	_ = r.newJSONScanner(bytes.NewReader(data), &out).root()
+
+	return out.String()
+}
+
+// jsonWriter is the output of the jsonScanner, as a *bytes.Buffer or a *bufio.Writer, whose write errors
+// are returned by Flush.
+type jsonWriter interface {
+	io.Writer
+	io.ByteWriter
+	io.StringWriter
+}
+
+type jsonScanner struct {
+	in     io.ByteScanner
+	out    jsonWriter
+	token  []byte // The raw string or literal scanned last, reused by the next one.
+	walker walker
+}
+
+func (r *Redactor) newJSONScanner(in io.ByteScanner, out jsonWriter) *jsonScanner {
+	return &jsonScanner{in: in, out: out, walker: walker{redactor: r, bypass: ignoreRedacted()}}
+}
+
+// root keeps the quirks of Redact: a string is logged as {"plain/text-type": ...}, as are the strings of
+// an array.
+func (s *jsonScanner) root() error {
+	c, err := s.peekValue()
+	if err != nil {
+		return err
+	}
+
+	switch c {
+	case '"':
+		err = s.plainText()
+	case '[':
+		err = s.array(nil, true)
+	default:
+		err = s.value(nil)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := s.skipSpaces(); err != nil {
+		return err
+	}
+
+	if _, err := s.peek(); err != io.EOF {
+		if err != nil {
+			return err
+		}
+
+		return errInvalidJSON
+	}
+
+	return nil
+}
+
+func (s *jsonScanner) plainText() error {
+	raw, err := s.scanString()
+	if err != nil {
+		return err
+	}
+
+	value, err := unquote(raw)
+	if err != nil {
+		return err
+	}
+
+	_, _ = s.out.WriteString(`{"plain/text-type":`)
+	writeString(s.out, s.walker.redactor.detect(value, s.walker.bypass))
+	_ = s.out.WriteByte('}')
+
+	return nil
+}
+
+func (s *jsonScanner) value(path []pathElem) error {
+	c, err := s.peekValue()
+	if err != nil {
+		return err
+	}
+
+	switch c {
+	case '{':
+		return s.object(path)
+	case '[':
+		return s.array(path, false)
+	case '"':
+		raw, err := s.scanString()
+		if err != nil {
+			return err
+		}
+
+		return s.leaf(raw, true, path)
+	default:
+		raw, err := s.scanLiteral()
+		if err != nil {
+			return err
+		}
+
+		return s.leaf(raw, false, path)
+	}
+}
+
+func (s *jsonScanner) object(path []pathElem) error {
+	redacted, restore := s.walker.container(path)
+	defer restore()
+
+	if redacted {
+		writeString(s.out, REDACTED)
+		return s.skipValue()
+	}
+
+	_, _ = s.in.ReadByte()
+	_ = s.out.WriteByte('{')
+
+	emitted := false
+
+	for first := true; ; first = false {
+		c, err := s.peekValue()
+		if err != nil {
+			return err
+		}
+
+		if c == '}' && first {
+			_, _ = s.in.ReadByte()
+			break
+		}
+
+		rawKey, err := s.scanString()
+		if err != nil {
+			return err
+		}
+
+		key, err := unquote(rawKey)
+		if err != nil {
+			return err
+		}
+
+		if c, err := s.peekValue(); err != nil || c != ':' {
+			return invalidJSON(err)
+		}
+
+		_, _ = s.in.ReadByte()
+
+		c, err = s.peekValue()
+		if err != nil {
+			return err
+		}
+
+		// Redact skips the null values of the objects.
+		if c == 'n' {
+			if raw, err := s.scanLiteral(); err != nil || string(raw) != "null" {
+				return invalidJSON(err)
+			}
+		} else {
+			if emitted {
+				_ = s.out.WriteByte(',')
+			}
+
+			emitted = true
+
+			// The raw key is still the token, as nothing was scanned after it.
+			_, _ = s.out.Write(rawKey)
+			_ = s.out.WriteByte(':')
+
+			if err := s.value(append(path, pathElem{key: key})); err != nil {
+				return err
+			}
+		}
+
+		c, err = s.peekValue()
+		if err != nil {
+			return err
+		}
+
+		_, _ = s.in.ReadByte()
+
+		if c == ',' {
+			continue
+		}
+
+		if c != '}' {
+			return errInvalidJSON
+		}
+
+		break
+	}
+
+	_ = s.out.WriteByte('}')
+
+	return nil
+}
+
+func (s *jsonScanner) array(path []pathElem, root bool) error {
+	redacted, restore := s.walker.container(path)
+	defer restore()
+
+	if redacted {
+		writeString(s.out, REDACTED)
+		return s.skipValue()
+	}
+
+	_, _ = s.in.ReadByte()
+	_ = s.out.WriteByte('[')
+
+	for i := 0; ; i++ {
+		c, err := s.peekValue()
+		if err != nil {
+			return err
+		}
+
+		if c == ']' && i == 0 {
+			_, _ = s.in.ReadByte()
+			break
+		}
+
+		if i > 0 {
+			_ = s.out.WriteByte(',')
+		}
+
+		if root && c == '"' {
+			err = s.plainText()
+		} else {
+			err = s.value(append(path, pathElem{index: i, isIndex: true}))
+		}
+
+		if err != nil {
+			return err
+		}
+
+		c, err = s.peekValue()
+		if err != nil {
+			return err
+		}
+
+		_, _ = s.in.ReadByte()
+
+		if c == ',' {
+			continue
+		}
+
+		if c != ']' {
+			return errInvalidJSON
+		}
+
+		break
+	}
+
+	_ = s.out.WriteByte(']')
+
+	return nil
+}
+
+// leaf writes a string, number, boolean or null, copying it as is unless a rule or detector changes it.
+func (s *jsonScanner) leaf(raw []byte, isString bool, path []pathElem) error {
+	if !isString && string(raw) == "null" {
+		_, _ = s.out.Write(raw)
+		return nil
+	}
+
+	rule, ok := compiledRule{}, false
+	if len(path) > 0 {
+		rule, ok = s.walker.redactor.match(path, s.walker.bypass)
+	}
+
+	if !ok && s.walker.inherited != nil {
+		rule, ok = *s.walker.inherited, true
+	}
+
+	if !ok && (!isString || len(s.walker.redactor.detectors) == 0) {
+		_, _ = s.out.Write(raw)
+		return nil
+	}
+
+	value := string(raw)
+	if isString {
+		var err error
+		if value, err = unquote(raw); err != nil {
+			return err
+		}
+	}
+
+	if ok {
+		writeString(s.out, rule.apply(value))
+		return nil
+	}
+
+	if detected := s.walker.redactor.detect(value, s.walker.bypass); detected != value {
+		writeString(s.out, detected)
+		return nil
+	}
+
+	_, _ = s.out.Write(raw)
+
+	return nil
+}
+
+// peek returns the next byte without reading it.
+func (s *jsonScanner) peek() (byte, error) {
+	c, err := s.in.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	return c, s.in.UnreadByte()
+}
+
+// peekValue skips the spaces and returns the next byte, which must exist.
+func (s *jsonScanner) peekValue() (byte, error) {
+	if err := s.skipSpaces(); err != nil {
+		return 0, err
+	}
+
+	c, err := s.peek()
+	if err != nil {
+		return 0, invalidJSON(err)
+	}
+
+	return c, nil
+}
+
+func (s *jsonScanner) skipSpaces() error {
+	for {
+		c, err := s.in.ReadByte()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		switch c {
+		case ' ', '\t', '\n', '\r':
+		default:
+			return s.in.UnreadByte()
+		}
+	}
+}
+
+// scanString reads the raw quoted string, valid until the next scan.
+func (s *jsonScanner) scanString() ([]byte, error) {
+	if c, err := s.in.ReadByte(); err != nil || c != '"' {
+		return nil, invalidJSON(err)
+	}
+
+	s.token = append(s.token[:0], '"')
+
+	for escaped := false; ; {
+		c, err := s.in.ReadByte()
+		if err != nil {
+			return nil, invalidJSON(err)
+		}
+
+		s.token = append(s.token, c)
+
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			return s.token, nil
+		}
+	}
+}
+
+// scanLiteral reads the raw number, boolean or null, valid until the next scan.
+func (s *jsonScanner) scanLiteral() ([]byte, error) {
+	s.token = s.token[:0]
+
+	for {
+		c, err := s.in.ReadByte()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if c == ',' || c == '}' || c == ']' || c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			if err := s.in.UnreadByte(); err != nil {
+				return nil, err
+			}
+
+			break
+		}
+
+		s.token = append(s.token, c)
+	}
+
+	switch string(s.token) {
+	case "true", "false", "null":
+		return s.token, nil
+	}
+
+	if len(s.token) == 0 || !json.Valid(s.token) {
+		return nil, errInvalidJSON
+	}
+
+	return s.token, nil
+}
+
+// skipValue reads past the value, validating its structure.
+func (s *jsonScanner) skipValue() error {
+	c, err := s.peekValue()
+	if err != nil {
+		return err
+	}
+
+	switch c {
+	case '"':
+		_, err := s.scanString()
+		return err
+	case '{', '[':
+		for depth := 0; ; {
+			c, err := s.peek()
+			if err != nil {
+				return invalidJSON(err)
+			}
+
+			if c == '"' {
+				if _, err := s.scanString(); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			_, _ = s.in.ReadByte()
+
+			switch c {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+
+			if depth == 0 {
+				return nil
+			}
+		}
+	default:
+		_, err := s.scanLiteral()
+		return err
+	}
+}
+
+// invalidJSON returns errInvalidJSON for an unexpected byte (nil) or a JSON ending early, and the read
+// errors as they are.
+func invalidJSON(err error) error {
+	if err == nil || err == io.EOF {
+		return errInvalidJSON
+	}
+
+	return err
+}
+
+func unquote(raw []byte) (string, error) {
+	if bytes.IndexByte(raw, '\\') == -1 && utf8.Valid(raw) {
+		return string(raw[1 : len(raw)-1]), nil
+	}
+
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "", fmt.Errorf("%w: %v", errInvalidJSON, err)
+	}
+
+	return value, nil
+}
+
+func writeString(out jsonWriter, value string) {
+	quoted, _ := json.Marshal(value)
+	_, _ = out.Write(quoted)
+}
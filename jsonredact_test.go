@@ -0,0 +1,219 @@
+package liberlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestRedactor_RedactJSON(t *testing.T) {
+	SetTokenKey([]byte("secret"))
+	defer SetTokenKey(nil)
+
+	redactor, err := NewRedactor(
+		append(append(RedactRules("password", "payer.document", "items[*].card"), MaskRules("name", "items[1]")...), TokenizeRules("customer")...),
+		WithMaskers(map[string]Masker{"email": EmailMasker}),
+		WithDetectors(CPFDetector(ActionMask)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "Should redact an object", body: `{"name": "Joao Silva", "password": "123", "payer": {"document": "52998224725", "name": "Ana"}}`},
+		{name: "Should redact the arrays", body: `{"items": [{"id": 1, "card": {"number": "5111786674841746"}}, {"id": 2, "price": 10.5, "tags": ["a", null]}]}`},
+		{name: "Should skip the null values of objects", body: `{"a": null, "b": {"c": null, "d": true}, "e": []}`},
+		{name: "Should mask numbers and escaped strings", body: `{"name": 123456, "email": "joão@liber.com", "notes": "cpf 529.982.247-25 \"quoted\""}`},
+		{name: "Should tokenize", body: `{"customer": "42", "nested": {"customer": {"id": 1}}}`},
+		{name: "Should wrap a plain text", body: `"529.982.247-25"`},
+		{name: "Should wrap the strings of a root array", body: ` [ "text", {"password": "123"}, 1, null ] `},
+		{name: "Should keep a number", body: `10`},
+		{name: "Should keep an empty object", body: `{ }`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var parsed interface{}
+			if err := json.Unmarshal([]byte(tt.body), &parsed); err != nil {
+				t.Fatal(err)
+			}
+
+			want, _ := json.Marshal(redactor.Redact(parsed))
+
+			got, err := redactor.RedactJSON([]byte(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !jsonEqual(t, got, want) {
+				t.Errorf("RedactJSON() = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestRedactor_RedactJSON_invalid(t *testing.T) {
+	redactor, _ := NewRedactor(RedactRules("password"))
+
+	for _, body := range []string{``, `{`, `{"a" 1}`, `{"a": 1,}`, `[1 2]`, `{"a": tru}`, `{"password": {"a": 1}`, `{} {}`, `"open`} {
+		if got, err := redactor.RedactJSON([]byte(body)); err == nil {
+			t.Errorf("RedactJSON(%s) = %s, want an error", body, got)
+		}
+	}
+}
+
+func TestRedactor_RedactJSONStream(t *testing.T) {
+	redactor, _ := NewRedactor(append(RedactRules("password", "payer"), MaskRules("name")...))
+
+	bodies := []string{
+		`{"name": "Joao Silva", "password": "123", "payer": {"document": "52998224725"}, "a": null, "b": [1, {}]}`,
+		` [ "text", {"password": "123"}, 1.5e3, null ] `,
+		`"plain"`,
+		`true`,
+	}
+	for _, body := range bodies {
+		want, err := redactor.RedactJSON([]byte(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// OneByteReader is not an io.ByteScanner, so the stream is read through a bufio.Reader.
+		for _, reader := range []io.Reader{strings.NewReader(body), iotest.OneByteReader(strings.NewReader(body))} {
+			out := &bytes.Buffer{}
+
+			if err := redactor.RedactJSONStream(out, reader); err != nil {
+				t.Fatal(err)
+			}
+
+			if out.String() != string(want) {
+				t.Errorf("RedactJSONStream(%s) = %s, want %s", body, out, want)
+			}
+		}
+
+		if got, err := redactor.RedactJSONReader(strings.NewReader(body)); err != nil || string(got) != string(want) {
+			t.Errorf("RedactJSONReader(%s) = %s, %v, want %s", body, got, err, want)
+		}
+	}
+}
+
+func TestRedactor_RedactJSONStream_errors(t *testing.T) {
+	redactor, _ := NewRedactor(RedactRules("password"))
+	errRead := errors.New("read failed")
+	errWrite := errors.New("write failed")
+
+	tests := []struct {
+		name   string
+		writer io.Writer
+		reader io.Reader
+		want   error
+	}{
+		{
+			name:   "Should return an invalid JSON",
+			writer: io.Discard,
+			reader: strings.NewReader(`{"password": "123", "a": `),
+			want:   errInvalidJSON,
+		},
+		{
+			name:   "Should return the read error",
+			writer: io.Discard,
+			reader: io.MultiReader(strings.NewReader(`{"password": "1`), iotest.ErrReader(errRead)),
+			want:   errRead,
+		},
+		{
+			name:   "Should return the write error",
+			writer: failingWriter{err: errWrite},
+			reader: strings.NewReader(`{"a": 1}`),
+			want:   errWrite,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := redactor.RedactJSONStream(tt.writer, tt.reader); !errors.Is(err, tt.want) {
+				t.Errorf("RedactJSONStream() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+type failingWriter struct {
+	err error
+}
+
+func (w failingWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
+
+func jsonEqual(t *testing.T, a []byte, b []byte) bool {
+	t.Helper()
+
+	var valueA, valueB interface{}
+
+	if err := json.Unmarshal(a, &valueA); err != nil {
+		t.Fatalf("invalid JSON %s: %v", a, err)
+	}
+
+	if err := json.Unmarshal(b, &valueB); err != nil {
+		t.Fatalf("invalid JSON %s: %v", b, err)
+	}
+
+	return reflect.DeepEqual(valueA, valueB)
+}
+
+func benchmarkBody() []byte {
+	items := make([]string, 0, 2000)
+
+	for i := 0; i < 2000; i++ {
+		items = append(items, fmt.Sprintf(
+			`{"id": %d, "description": "invoice %d of the partner", "amount": %d.50, "payer": {"name": "Joao Silva", "document": "52998224725"}, "tags": ["a", "b"]}`,
+			i, i, i,
+		))
+	}
+
+	return []byte(`{"items": [` + strings.Join(items, ",") + `], "token": "abc"}`)
+}
+
+func BenchmarkRedactor_Redact(b *testing.B) {
+	body := benchmarkBody()
+	redactor, _ := NewRedactor(append(RedactRules("token", "payer.document"), MaskRules("name")...))
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+
+	for i := 0; i < b.N; i++ {
+		var parsed interface{}
+		_ = json.Unmarshal(body, &parsed)
+		_, _ = json.Marshal(redactor.Redact(parsed))
+	}
+}
+
+func BenchmarkRedactor_RedactJSON(b *testing.B) {
+	body := benchmarkBody()
+	redactor, _ := NewRedactor(append(RedactRules("token", "payer.document"), MaskRules("name")...))
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+
+	for i := 0; i < b.N; i++ {
+		_, _ = redactor.RedactJSON(body)
+	}
+}
+
+func BenchmarkRedactor_RedactJSONStream(b *testing.B) {
+	body := benchmarkBody()
+	redactor, _ := NewRedactor(append(RedactRules("token", "payer.document"), MaskRules("name")...))
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+
+	for i := 0; i < b.N; i++ {
+		_ = redactor.RedactJSONStream(io.Discard, bytes.NewReader(body))
+	}
+}
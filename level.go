@@ -0,0 +1,120 @@
+package liberlogger
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// levelVar is a log level that can be read and changed while the logger is in use.
+type levelVar struct {
+	level  atomic.Int32
+	global bool // The level is the zerolog global level, set by Init.
+
+	mu       sync.Mutex
+	revert   *time.Timer
+	revertTo zerolog.Level
+}
+
+func newLevelVar(level zerolog.Level) *levelVar {
+	v := &levelVar{}
+	v.Set(level)
+
+	return v
+}
+
+// newGlobalLevelVar returns the level of the Default logger until InitWithConfig, which is the zerolog
+// global level, so the runtime changes reach the services using Init and log.Logger.
+func newGlobalLevelVar() *levelVar {
+	return &levelVar{global: true}
+}
+
+func (v *levelVar) Get() zerolog.Level {
+	if v.global {
+		return zerolog.GlobalLevel()
+	}
+
+	return zerolog.Level(v.level.Load())
+}
+
+func (v *levelVar) Set(level zerolog.Level) {
+	if v.global {
+		zerolog.SetGlobalLevel(level)
+		return
+	}
+
+	v.level.Store(int32(level))
+}
+
+// ChangeLevel changes the level of the Logger, and of every child sharing it, logging an audit line
+// with the source of the change. When ttl is greater than zero the level is reverted after it.
+func (l *Logger) ChangeLevel(ctx context.Context, level zerolog.Level, ttl time.Duration, source string) {
+	v := l.level
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	from := v.Get()
+	revertTo := from
+
+	if v.revert != nil && v.revert.Stop() {
+		revertTo = v.revertTo
+	}
+
+	v.revert = nil
+
+	if ttl > 0 {
+		var timer *time.Timer
+
+		timer = time.AfterFunc(ttl, func() {
+			v.mu.Lock()
+			defer v.mu.Unlock()
+
+			if v.revert != timer {
+				return
+			}
+
+			current := v.Get()
+
+			v.Set(v.revertTo)
+			v.revert = nil
+
+			l.auditLevel(ctx, current, v.revertTo, 0, source+" ttl")
+		})
+
+		v.revert = timer
+		v.revertTo = revertTo
+	}
+
+	v.Set(level)
+
+	l.auditLevel(ctx, from, level, ttl, source)
+}
+
+func (l *Logger) auditLevel(ctx context.Context, from zerolog.Level, to zerolog.Level, ttl time.Duration, source string) {
+	event := l.zerologger().Log().
+		Fields(l.fields).
+		Fields(ctx.Value(LogFieldsKey{})).
+		Str("from", from.String()).
+		Str("to", to.String()).
+		Str("source", source)
+
+	if ttl > 0 {
+		event.Str("ttl", ttl.String())
+	}
+
+	event.Msg("liberlogger | Log level changed")
+}
+
+func lookupLevel(logLevel string) (zerolog.Level, bool) {
+	switch strings.ToLower(logLevel) {
+	case fatalLevel, errorLevel, warnLevel, infoLevel, debugLevel:
+		return parseLevel(logLevel), true
+	}
+
+	return zerolog.NoLevel, false
+}
@@ -0,0 +1,69 @@
+package liberlogger
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+type levelPayload struct {
+	Level string `json:"level"`
+	TTL   string `json:"ttl,omitempty"`
+}
+
+// LevelHandler returns a http.Handler to read (GET) and change (PUT) the level of the Default logger.
+//
+// The PUT body is {"level": "debug", "ttl": "15m"}, where ttl is optional and reverts the level after it.
+func LevelHandler() http.Handler {
+	return levelHandler(Default)
+}
+
+// LevelHandler returns a http.Handler to read (GET) and change (PUT) the level of the Logger.
+func (l *Logger) LevelHandler() http.Handler {
+	return levelHandler(func() *Logger { return l })
+}
+
+func levelHandler(logger func() *Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l := logger()
+
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var payload levelPayload
+
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				http.Error(w, "invalid body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			level, ok := lookupLevel(payload.Level)
+			if !ok {
+				http.Error(w, "invalid level: "+payload.Level, http.StatusBadRequest)
+				return
+			}
+
+			var ttl time.Duration
+
+			if payload.TTL != "" {
+				var err error
+
+				ttl, err = time.ParseDuration(payload.TTL)
+				if err != nil || ttl < 0 {
+					http.Error(w, "invalid ttl: "+payload.TTL, http.StatusBadRequest)
+					return
+				}
+			}
+
+			l.ChangeLevel(r.Context(), level, ttl, "http "+r.RemoteAddr)
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		_ = json.NewEncoder(w).Encode(levelPayload{Level: l.Level().String()})
+	})
+}
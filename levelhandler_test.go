@@ -0,0 +1,110 @@
+package liberlogger
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestLevelHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+		wantLevel  zerolog.Level
+	}{
+		{
+			name:       "Should return the current level",
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantLevel:  zerolog.InfoLevel,
+		},
+		{
+			name:       "Should change the level",
+			method:     http.MethodPut,
+			body:       `{"level":"debug"}`,
+			wantStatus: http.StatusOK,
+			wantLevel:  zerolog.DebugLevel,
+		},
+		{
+			name:       "Should not change to an invalid level",
+			method:     http.MethodPut,
+			body:       `{"level":"verbose"}`,
+			wantStatus: http.StatusBadRequest,
+			wantLevel:  zerolog.InfoLevel,
+		},
+		{
+			name:       "Should not accept other methods",
+			method:     http.MethodPost,
+			body:       `{"level":"debug"}`,
+			wantStatus: http.StatusMethodNotAllowed,
+			wantLevel:  zerolog.InfoLevel,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := New(WithOutput(io.Discard))
+
+			rec := httptest.NewRecorder()
+			logger.LevelHandler().ServeHTTP(rec, httptest.NewRequest(tt.method, "/log-level", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			if logger.Level() != tt.wantLevel {
+				t.Errorf("level = %s, want %s", logger.Level(), tt.wantLevel)
+			}
+		})
+	}
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+func TestLevelHandlerTTL(t *testing.T) {
+	var out syncBuffer
+
+	logger := New(WithOutput(&out))
+
+	rec := httptest.NewRecorder()
+	logger.LevelHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/log-level", strings.NewReader(`{"level":"debug","ttl":"20ms"}`)))
+
+	if logger.Level() != zerolog.DebugLevel {
+		t.Fatalf("level = %s, want debug", logger.Level())
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if logger.Level() != zerolog.InfoLevel {
+		t.Errorf("level = %s, want info after the ttl", logger.Level())
+	}
+
+	if strings.Count(out.String(), "Log level changed") != 2 {
+		t.Errorf("audit log = %s, want the change and the revert", out.String())
+	}
+}
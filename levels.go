@@ -0,0 +1,41 @@
+package liberlogger
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// LevelMapper returns the level of the response log of the status and the error. The status is 0 when the
+// HttpClient has no response.
+type LevelMapper func(status int, err error) zerolog.Level
+
+// StatusLevel is the default LevelMapper of the middlewares and the HttpClient: info for 1xx, 2xx and 3xx,
+// warn for 4xx and error for 5xx and the requests without a response.
+func StatusLevel(status int, err error) zerolog.Level {
+	switch {
+	case status >= http.StatusInternalServerError, status == 0 && err != nil:
+		return zerolog.ErrorLevel
+	case status >= http.StatusBadRequest:
+		return zerolog.WarnLevel
+	default:
+		return zerolog.InfoLevel
+	}
+}
+
+// responseLevel maps the response with levels, StatusLevel when nil, promoting it to warn when the
+// duration reaches the slow threshold.
+func responseLevel(levels LevelMapper, slowThreshold time.Duration, status int, err error, duration time.Duration) zerolog.Level {
+	if levels == nil {
+		levels = StatusLevel
+	}
+
+	level := levels(status, err)
+
+	if slowThreshold > 0 && duration >= slowThreshold && level < zerolog.WarnLevel {
+		level = zerolog.WarnLevel
+	}
+
+	return level
+}
@@ -0,0 +1,283 @@
+package liberlogger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestStatusLevel(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		err    error
+		want   zerolog.Level
+	}{
+		{
+			name:   "Should map 2xx to info",
+			status: http.StatusCreated,
+			want:   zerolog.InfoLevel,
+		},
+		{
+			name:   "Should map 3xx to info",
+			status: http.StatusFound,
+			want:   zerolog.InfoLevel,
+		},
+		{
+			name:   "Should map 4xx to warn",
+			status: http.StatusNotFound,
+			want:   zerolog.WarnLevel,
+		},
+		{
+			name:   "Should map 4xx with an error to warn",
+			status: http.StatusBadRequest,
+			err:    errors.New("invalid user"),
+			want:   zerolog.WarnLevel,
+		},
+		{
+			name:   "Should map 5xx to error",
+			status: http.StatusServiceUnavailable,
+			want:   zerolog.ErrorLevel,
+		},
+		{
+			name: "Should map the error without a response to error",
+			err:  errors.New("connection refused"),
+			want: zerolog.ErrorLevel,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatusLevel(tt.status, tt.err); got != tt.want {
+				t.Errorf("StatusLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponseLevel(t *testing.T) {
+	tests := []struct {
+		name          string
+		levels        LevelMapper
+		slowThreshold time.Duration
+		status        int
+		duration      time.Duration
+		want          zerolog.Level
+	}{
+		{
+			name:     "Should use StatusLevel when the levels are nil",
+			status:   http.StatusInternalServerError,
+			duration: time.Second,
+			want:     zerolog.ErrorLevel,
+		},
+		{
+			name:          "Should promote the slow response to warn",
+			slowThreshold: time.Second,
+			status:        http.StatusOK,
+			duration:      2 * time.Second,
+			want:          zerolog.WarnLevel,
+		},
+		{
+			name:          "Should not promote the response faster than the threshold",
+			slowThreshold: time.Second,
+			status:        http.StatusOK,
+			duration:      time.Millisecond,
+			want:          zerolog.InfoLevel,
+		},
+		{
+			name:          "Should not demote the slow response over warn",
+			slowThreshold: time.Second,
+			status:        http.StatusBadGateway,
+			duration:      2 * time.Second,
+			want:          zerolog.ErrorLevel,
+		},
+		{
+			name:          "Should promote the slow response of the levels",
+			levels:        func(status int, err error) zerolog.Level { return zerolog.DebugLevel },
+			slowThreshold: time.Second,
+			status:        http.StatusOK,
+			duration:      time.Second,
+			want:          zerolog.WarnLevel,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := responseLevel(tt.levels, tt.slowThreshold, tt.status, nil, tt.duration); got != tt.want {
+				t.Errorf("responseLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPMiddlewareStatusLevels(t *testing.T) {
+	notFoundInfo, err := NewRouteRules(RouteRule{
+		Pattern: "/users",
+		Levels: func(status int, err error) zerolog.Level {
+			if status == http.StatusNotFound {
+				return zerolog.InfoLevel
+			}
+			return StatusLevel(status, err)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slowUsers, err := NewRouteRules(RouteRule{Pattern: "/users", SlowThreshold: time.Nanosecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		opts      []MiddlewareOption
+		status    int
+		wantLevel string
+	}{
+		{
+			name:      "Should log the 2xx response as info",
+			status:    http.StatusOK,
+			wantLevel: "info",
+		},
+		{
+			name:      "Should log the 4xx response as warn",
+			status:    http.StatusUnprocessableEntity,
+			wantLevel: "warn",
+		},
+		{
+			name:      "Should log the 5xx response as error",
+			status:    http.StatusInternalServerError,
+			wantLevel: "error",
+		},
+		{
+			name:      "Should log the slow response as warn",
+			opts:      []MiddlewareOption{MiddlewareSlowThreshold(time.Nanosecond)},
+			status:    http.StatusOK,
+			wantLevel: "warn",
+		},
+		{
+			name:      "Should log with the levels of the route rule without an action",
+			opts:      []MiddlewareOption{MiddlewareRouteRules(notFoundInfo)},
+			status:    http.StatusNotFound,
+			wantLevel: "info",
+		},
+		{
+			name:      "Should log with the slow threshold of the route rule",
+			opts:      []MiddlewareOption{MiddlewareSlowThreshold(time.Hour), MiddlewareRouteRules(slowUsers)},
+			status:    http.StatusOK,
+			wantLevel: "warn",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(time.Microsecond)
+				w.WriteHeader(tt.status)
+			})
+
+			server := withLogger(New(WithOutput(&out)), HTTPMiddleware(tt.opts...)(handler))
+			server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+
+			logs := readLogs(t, &out)
+			if len(logs) != 2 {
+				t.Fatalf("logs = %v, want the request and the response", logs)
+			}
+
+			if logs[1]["level"] != tt.wantLevel {
+				t.Errorf("response level = %v, want %v", logs[1]["level"], tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestHttpClientStatusLevels(t *testing.T) {
+	tests := []struct {
+		name      string
+		client    HttpClient
+		status    int
+		err       error
+		wantLevel string
+	}{
+		{
+			name:      "Should log the 2xx response as info",
+			status:    http.StatusOK,
+			wantLevel: "info",
+		},
+		{
+			name:      "Should log the 4xx response as warn",
+			status:    http.StatusConflict,
+			wantLevel: "warn",
+		},
+		{
+			name:      "Should log the 5xx response as error",
+			status:    http.StatusServiceUnavailable,
+			wantLevel: "error",
+		},
+		{
+			name:      "Should log the request without a response as error",
+			err:       errors.New("connection refused"),
+			wantLevel: "error",
+		},
+		{
+			name:      "Should log the slow response as warn",
+			client:    HttpClient{SlowThreshold: time.Nanosecond},
+			status:    http.StatusOK,
+			wantLevel: "warn",
+		},
+		{
+			name: "Should log with the levels of the client",
+			client: HttpClient{Levels: func(status int, err error) zerolog.Level {
+				return zerolog.DebugLevel
+			}},
+			status:    http.StatusServiceUnavailable,
+			wantLevel: "debug",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			client := tt.client
+			client.Proxied = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				time.Sleep(time.Microsecond)
+
+				if tt.err != nil {
+					return nil, tt.err
+				}
+
+				return &http.Response{
+					StatusCode: tt.status,
+					Header:     http.Header{},
+					Body:       io.NopCloser(strings.NewReader("")),
+					Request:    req,
+				}, nil
+			})
+
+			logger := New(WithOutput(&out), WithLevel("debug"))
+
+			req, _ := http.NewRequestWithContext(logger.WithContext(context.Background()), http.MethodGet, "https://partner.com/token", nil)
+
+			if res, err := client.RoundTrip(req); err == nil {
+				res.Body.Close()
+			}
+
+			logs := readLogs(t, &out)
+			if len(logs) != 2 {
+				t.Fatalf("logs = %v, want the request and the response", logs)
+			}
+
+			if logs[1]["level"] != tt.wantLevel {
+				t.Errorf("response level = %v, want %v", logs[1]["level"], tt.wantLevel)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+//go:build !windows
+
+package liberlogger
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rs/zerolog"
+)
+
+// NotifyLevelSignals changes the Default logger to debug when the process receives SIGUSR1, and back to
+// the level it had before on SIGUSR2. The Default logger is resolved on each signal, so it can be called
+// before InitWithConfig. It stops listening when ctx is done.
+func NotifyLevelSignals(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+
+	go func() {
+		defer signal.Stop(signals)
+
+		// The logger changed to debug by SIGUSR1 and its level before it.
+		var debugLogger *Logger
+		var configured zerolog.Level
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-signals:
+				logger := Default()
+
+				switch {
+				case sig == syscall.SIGUSR1:
+					if debugLogger != logger {
+						debugLogger, configured = logger, logger.Level()
+					}
+
+					logger.ChangeLevel(ctx, zerolog.DebugLevel, 0, "signal "+sig.String())
+				case debugLogger == logger:
+					debugLogger = nil
+
+					logger.ChangeLevel(ctx, configured, 0, "signal "+sig.String())
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,56 @@
+//go:build !windows
+
+package liberlogger
+
+import (
+	"context"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestNotifyLevelSignals(t *testing.T) {
+	saveGlobals(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	NotifyLevelSignals(ctx)
+
+	var out syncBuffer
+
+	// The Default logger set after NotifyLevelSignals, as by InitWithConfig.
+	logger := New(WithOutput(&out), WithLevel("warn"))
+	SetDefault(logger)
+
+	tests := []struct {
+		name   string
+		signal syscall.Signal
+		want   zerolog.Level
+		audits int
+	}{
+		{name: "Should change the Default logger to debug on SIGUSR1", signal: syscall.SIGUSR1, want: zerolog.DebugLevel, audits: 1},
+		{name: "Should change the Default logger back on SIGUSR2", signal: syscall.SIGUSR2, want: zerolog.WarnLevel, audits: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := syscall.Kill(syscall.Getpid(), tt.signal); err != nil {
+				t.Fatal(err)
+			}
+
+			// The audit line is written after the level changes, so waiting for it leaves the goroutine idle
+			// when the globals are restored.
+			deadline := time.Now().Add(2 * time.Second)
+			for strings.Count(out.String(), "Log level changed") != tt.audits && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			if logger.Level() != tt.want {
+				t.Errorf("level = %v, want %v", logger.Level(), tt.want)
+			}
+		})
+	}
+}
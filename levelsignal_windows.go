@@ -0,0 +1,8 @@
+//go:build windows
+
+package liberlogger
+
+import "context"
+
+// NotifyLevelSignals does nothing on windows, which has no SIGUSR1 and SIGUSR2.
+func NotifyLevelSignals(ctx context.Context) {}
@@ -3,7 +3,6 @@ package liberlogger
 import (
 	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/rs/zerolog"
 )
@@ -26,61 +25,56 @@ var DefaultKeysToMask = []string{
 	"cnpj",
 }
 
-func extraLogs(request interface{}, err error) *zerolog.Event {
+func extraLogs(request interface{}, err error, redactor *Redactor) *zerolog.Event {
 	log := zerolog.Dict()
 
 	if err != nil {
-		log.Interface("error", err.Error())
+		log.Str(FieldError, err.Error())
 	}
 
 	switch request := request.(type) {
 	case *http.Request:
-		log.
-			Interface("url", request.URL.String()).
-			Interface("method", request.Method)
+		requestFields(log, request, redactor)
 	case *http.Response:
 		log.
-			Interface("request", map[string]interface{}{
-				"url":    request.Request.URL.String(),
-				"method": request.Request.Method,
-			})
+			Dict(FieldRequest, requestFields(zerolog.Dict(), request.Request, redactor)).
+			Int(FieldStatus, request.StatusCode).
+			Str(FieldProto, request.Proto)
+
+		if request.ContentLength >= 0 {
+			log.Int64(FieldResponseContentLength, request.ContentLength)
+		}
+	case *LogResponseWriter:
+		requestFields(log, request.Request, redactor).
+			Int(FieldStatus, request.StatusCode).
+			Int64(FieldResponseContentLength, request.size)
 	}
 	return log
 }
 
-func formatFinalMsg(request interface{}, msg string) (finalMsg string) {
+func formatFinalMsg(request interface{}, msg string, redactor *Redactor) (finalMsg string) {
 	switch request := request.(type) {
 	case *http.Request:
 		finalMsg = fmt.Sprintf("%s %s %s",
 			msg,
 			request.Method,
-			request.URL.String(),
+			redactedURL(request, redactor),
 		)
 	case *http.Response:
 		finalMsg = fmt.Sprintf("%s %s %d %s",
 			msg,
 			request.Request.Method,
 			request.StatusCode,
-			request.Request.URL.String(),
+			redactedURL(request.Request, redactor),
 		)
 	case *LogResponseWriter:
 		finalMsg = fmt.Sprintf("%s %s %d %s",
 			msg,
 			request.Request.Method,
 			request.StatusCode,
-			request.Request.URL.String(),
+			redactedURL(request.Request, redactor),
 		)
 	}
 
 	return
 }
-
-func ignoreRoute(ignoreList []string, request *http.Request) bool {
-	for _, ignore := range ignoreList {
-		if strings.Compare(ignore, request.RequestURI) == 0 {
-			return true
-		}
-	}
-
-	return false
-}
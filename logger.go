@@ -0,0 +1,262 @@
+package liberlogger
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Logger is a logger with its own output, level, fields and redaction keys, so
+// libraries in the same binary don't interfere with each other.
+type Logger struct {
+	zl         *zerolog.Logger // nil means the package logger log.Logger
+	level      *levelVar
+	components *componentLevels
+	component  string
+	fields     map[string]interface{}
+	redactKeys []string
+	maskKeys   []string
+	redactor   *Redactor
+}
+
+type Option func(*Logger)
+
+type loggerKey struct{}
+
+var defaultLogger atomic.Pointer[Logger]
+
+func init() {
+	defaultLogger.Store(newInitLogger())
+}
+
+// newInitLogger returns the Default logger before InitWithConfig, writing through log.Logger at the
+// zerolog global level.
+func newInitLogger() *Logger {
+	return &Logger{
+		level:      newGlobalLevelVar(),
+		components: newComponentLevels(),
+	}
+}
+
+// New creates a Logger writing JSON to os.Stderr with info level, unless changed by opts.
+func New(opts ...Option) *Logger {
+	zl := zerolog.New(os.Stderr).With().Timestamp().Logger()
+
+	logger := &Logger{
+		zl:         &zl,
+		level:      newLevelVar(zerolog.InfoLevel),
+		components: newComponentLevels(),
+	}
+
+	for _, opt := range opts {
+		opt(logger)
+	}
+
+	return logger
+}
+
+// WithConfig sets the output, levels, static fields and redaction keys of the Logger from config.
+func WithConfig(config Config) Option {
+	return func(l *Logger) {
+		zl := newZerolog(config)
+		l.zl = &zl
+		l.level.Set(parseLevel(config.Level))
+		l.components.SetAll(config.ComponentLevels)
+		l.redactKeys = append(l.redactKeys, config.RedactKeys...)
+		l.maskKeys = append(l.maskKeys, config.MaskKeys...)
+	}
+}
+
+func WithOutput(writer io.Writer) Option {
+	return func(l *Logger) {
+		zl := l.zl.Output(writer)
+		l.zl = &zl
+	}
+}
+
+func WithZerolog(zl zerolog.Logger) Option {
+	return func(l *Logger) {
+		l.zl = &zl
+	}
+}
+
+func WithLevel(level string) Option {
+	return func(l *Logger) {
+		l.level.Set(parseLevel(level))
+	}
+}
+
+// WithStaticFields adds fields to every log of the Logger.
+func WithStaticFields(fields map[string]interface{}) Option {
+	return func(l *Logger) {
+		l.fields = mergeFields(l.fields, fields)
+	}
+}
+
+func WithRedactKeys(keys ...string) Option {
+	return func(l *Logger) {
+		l.redactKeys = append(l.redactKeys, keys...)
+	}
+}
+
+func WithMaskKeys(keys ...string) Option {
+	return func(l *Logger) {
+		l.maskKeys = append(l.maskKeys, keys...)
+	}
+}
+
+// WithRedactor sets the Redactor of the Logger, used instead of the redact and mask keys.
+func WithRedactor(redactor *Redactor) Option {
+	return func(l *Logger) {
+		l.redactor = redactor
+	}
+}
+
+// Default returns the Logger used by the package functions Info, Error and the others.
+func Default() *Logger {
+	return defaultLogger.Load()
+}
+
+// SetDefault replaces the Logger used by the package functions.
+func SetDefault(logger *Logger) {
+	defaultLogger.Store(logger)
+}
+
+// FromContext returns the Logger carried by ctx, or the Default one.
+func FromContext(ctx context.Context) *Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*Logger); ok && logger != nil {
+		return logger
+	}
+
+	return Default()
+}
+
+// WithContext returns a copy of ctx carrying the Logger, used by the package functions.
+func (l *Logger) WithContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// With returns a child Logger sharing the output and level, with fields added to every log.
+func (l *Logger) With(fields map[string]interface{}) *Logger {
+	child := *l
+	child.fields = mergeFields(l.fields, fields)
+
+	return &child
+}
+
+// Level returns the minimum level of the Logger, which is the level of its component when it has one.
+func (l *Logger) Level() zerolog.Level {
+	if level, ok := l.components.Get(l.component); ok {
+		return level
+	}
+
+	return l.level.Get()
+}
+
+func (l *Logger) SetLevel(level string) {
+	l.level.Set(parseLevel(level))
+}
+
+// Redact applies the Redactor, or the redact and mask keys, of the Logger to body.
+func (l *Logger) Redact(body interface{}) interface{} {
+	if l.redactor != nil {
+		return l.redactor.Redact(body)
+	}
+
+	return Redact(l.redactKeys, l.maskKeys, body)
+}
+
+func (l *Logger) Info(ctx context.Context) *zerolog.Event {
+	return l.newEvent(ctx, zerolog.InfoLevel)
+}
+
+func (l *Logger) Debug(ctx context.Context) *zerolog.Event {
+	return l.newEvent(ctx, zerolog.DebugLevel)
+}
+
+func (l *Logger) Warn(ctx context.Context) *zerolog.Event {
+	return l.newEvent(ctx, zerolog.WarnLevel)
+}
+
+func (l *Logger) Error(ctx context.Context, err error) *zerolog.Event {
+	return l.newEvent(ctx, zerolog.ErrorLevel).Stack().Err(err)
+}
+
+func (l *Logger) Panic(ctx context.Context, err error) *zerolog.Event {
+	return l.newEvent(ctx, zerolog.PanicLevel).Stack().Err(err)
+}
+
+func (l *Logger) Fatal(ctx context.Context, err error) *zerolog.Event {
+	return l.newEvent(ctx, zerolog.FatalLevel).Stack().Err(err)
+}
+
+// levelEvent returns an event of the level, at most error, with the error when not nil.
+func (l *Logger) levelEvent(ctx context.Context, level zerolog.Level, err error) *zerolog.Event {
+	if level >= zerolog.ErrorLevel {
+		return l.Error(ctx, err)
+	}
+
+	return l.newEvent(ctx, level).Err(err)
+}
+
+func (l *Logger) zerologger() *zerolog.Logger {
+	if l.zl == nil {
+		return &log.Logger
+	}
+
+	return l.zl
+}
+
+func (l *Logger) newEvent(ctx context.Context, level zerolog.Level) *zerolog.Event {
+	if level < l.Level() {
+		return nil
+	}
+
+	zl := l.zerologger()
+
+	// log.Logger drops the events below the global level, set by Init, so the ones of a component with
+	// a lower level are written without a level, adding the level field.
+	if l.zl == nil && level < zerolog.GlobalLevel() {
+		return zl.Log().
+			Str(zerolog.LevelFieldName, zerolog.LevelFieldMarshalFunc(level)).
+			Fields(l.fields).
+			Fields(ctx.Value(LogFieldsKey{}))
+	}
+
+	var event *zerolog.Event
+
+	switch level {
+	case zerolog.DebugLevel:
+		event = zl.Debug()
+	case zerolog.WarnLevel:
+		event = zl.Warn()
+	case zerolog.ErrorLevel:
+		event = zl.Error()
+	case zerolog.PanicLevel:
+		event = zl.Panic()
+	case zerolog.FatalLevel:
+		event = zl.Fatal()
+	default:
+		event = zl.Info()
+	}
+
+	return event.Fields(l.fields).Fields(ctx.Value(LogFieldsKey{}))
+}
+
+func mergeFields(parent map[string]interface{}, fields map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(parent)+len(fields))
+
+	for key, value := range parent {
+		merged[key] = value
+	}
+
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	return merged
+}
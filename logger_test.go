@@ -0,0 +1,108 @@
+package liberlogger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []Option
+		log       func(ctx context.Context, logger *Logger)
+		ctxFields map[string]interface{}
+		want      map[string]interface{}
+	}{
+		{
+			name: "Should log with the level of the instance",
+			opts: []Option{WithLevel("debug")},
+			log: func(ctx context.Context, logger *Logger) {
+				logger.Debug(ctx).Msg("debug msg")
+			},
+			want: map[string]interface{}{"level": "debug", "message": "debug msg"},
+		},
+		{
+			name: "Should not log below the level of the instance",
+			opts: []Option{WithLevel("warn")},
+			log: func(ctx context.Context, logger *Logger) {
+				logger.Info(ctx).Msg("info msg")
+			},
+		},
+		{
+			name: "Should log the static and the context fields",
+			opts: []Option{WithStaticFields(map[string]interface{}{"service": "invoice"})},
+			log: func(ctx context.Context, logger *Logger) {
+				logger.Info(ctx).Msg("info msg")
+			},
+			ctxFields: map[string]interface{}{"log_id": "123"},
+			want:      map[string]interface{}{"level": "info", "message": "info msg", "service": "invoice", "log_id": "123"},
+		},
+		{
+			name: "Should not log below the level of the component",
+			opts: []Option{WithComponentLevels(map[string]string{"gorm": "warn"})},
+			log: func(ctx context.Context, logger *Logger) {
+				logger.Component("gorm").Info(ctx).Msg("info msg")
+			},
+		},
+		{
+			name: "Should log with the level of the component below the level of the instance",
+			opts: []Option{WithComponentLevels(ParseComponentLevels("httpclient=debug, gorm=warn"))},
+			log: func(ctx context.Context, logger *Logger) {
+				logger.Component("httpclient").Debug(ctx).Msg("debug msg")
+			},
+			want: map[string]interface{}{"level": "debug", "message": "debug msg", "component": "httpclient"},
+		},
+		{
+			name: "Should log the error",
+			log: func(ctx context.Context, logger *Logger) {
+				logger.Error(ctx, errors.New("failed")).Msg("error msg")
+			},
+			want: map[string]interface{}{"level": "error", "message": "error msg", "error": "failed"},
+		},
+		{
+			name: "Should log through the package functions with the Logger from the context",
+			log: func(ctx context.Context, logger *Logger) {
+				Warn(logger.WithContext(ctx)).Msg("warn msg")
+			},
+			want: map[string]interface{}{"level": "warn", "message": "warn msg"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+
+			ctx := context.Background()
+			if tt.ctxFields != nil {
+				ctx = context.WithValue(ctx, LogFieldsKey{}, tt.ctxFields)
+			}
+
+			logger := New(append([]Option{WithOutput(&out)}, tt.opts...)...)
+
+			tt.log(ctx, logger)
+
+			if tt.want == nil {
+				if out.Len() > 0 {
+					t.Errorf("log = %s, want nothing", out.String())
+				}
+				return
+			}
+
+			var got map[string]interface{}
+			if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+				t.Fatalf("invalid log %q: %v", out.String(), err)
+			}
+
+			for key, value := range tt.want {
+				if got[key] != value {
+					t.Errorf("log[%s] = %v, want %v (%s)", key, got[key], value, strings.TrimSpace(out.String()))
+				}
+			}
+		})
+	}
+}
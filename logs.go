@@ -4,38 +4,31 @@ import (
 	"context"
 
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
 )
 
 // LogFieldsKey Key used for access the Value in the context.Context
 type LogFieldsKey struct{}
 
 func Info(ctx context.Context) *zerolog.Event {
-	fields := ctx.Value(LogFieldsKey{})
-	return log.Ctx(log.Logger.WithContext(ctx)).Info().Fields(fields)
+	return FromContext(ctx).Info(ctx)
 }
 
 func Debug(ctx context.Context) *zerolog.Event {
-	fields := ctx.Value(LogFieldsKey{})
-	return log.Ctx(log.Logger.WithContext(ctx)).Debug().Fields(fields)
+	return FromContext(ctx).Debug(ctx)
 }
 
 func Warn(ctx context.Context) *zerolog.Event {
-	fields := ctx.Value(LogFieldsKey{})
-	return log.Ctx(log.Logger.WithContext(ctx)).Warn().Fields(fields)
+	return FromContext(ctx).Warn(ctx)
 }
 
 func Error(ctx context.Context, err error) *zerolog.Event {
-	fields := ctx.Value(LogFieldsKey{})
-	return log.Ctx(log.Logger.WithContext(ctx)).Error().Fields(fields).Stack().Err(err)
+	return FromContext(ctx).Error(ctx, err)
 }
 
 func Panic(ctx context.Context, err error) *zerolog.Event {
-	fields := ctx.Value(LogFieldsKey{})
-	return log.Ctx(log.Logger.WithContext(ctx)).Panic().Fields(fields).Stack().Err(err)
+	return FromContext(ctx).Panic(ctx, err)
 }
 
 func Fatal(ctx context.Context, err error) *zerolog.Event {
-	fields := ctx.Value(LogFieldsKey{})
-	return log.Ctx(log.Logger.WithContext(ctx)).Fatal().Fields(fields).Stack().Err(err)
+	return FromContext(ctx).Fatal(ctx, err)
 }
@@ -0,0 +1,156 @@
+package liberlogger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Masker hides part of a value, keeping enough of it to be recognised in the logs.
+type Masker interface {
+	Mask(value string) string
+}
+
+// MaskerFunc adapts a function to a Masker.
+type MaskerFunc func(value string) string
+
+func (f MaskerFunc) Mask(value string) string {
+	return f(value)
+}
+
+var (
+	// GenericMasker hides the middle third of the value.
+	GenericMasker Masker = MaskerFunc(maskValue)
+	// CPFMasker hides the first 3 and the check digits, keeping the punctuation: ***.982.247-**.
+	CPFMasker Masker = MaskerFunc(maskCPF)
+	// CNPJMasker hides the digits of the root after the first 2, keeping the punctuation: 11.***.***/0001-81.
+	CNPJMasker Masker = MaskerFunc(maskCNPJ)
+	// PANMasker keeps the first 6 and last 4 digits of a card number, as required by PCI DSS.
+	PANMasker Masker = MaskerFunc(maskPAN)
+	// EmailMasker keeps the first letter of the user and the domain: j*******@liber.com.
+	EmailMasker Masker = MaskerFunc(maskEmail)
+	// PhoneMasker keeps the country and area codes and the last 4 digits: +55 (11) *****-4321.
+	PhoneMasker Masker = MaskerFunc(maskPhone)
+)
+
+var maskers = map[string]Masker{
+	"generic": GenericMasker,
+	"cpf":     CPFMasker,
+	"cnpj":    CNPJMasker,
+	"pan":     PANMasker,
+	"email":   EmailMasker,
+	"phone":   PhoneMasker,
+}
+
+// LookupMasker returns the built-in Masker by its name: generic, cpf, cnpj, pan, email or phone.
+func LookupMasker(name string) (Masker, bool) {
+	masker, ok := maskers[strings.ToLower(name)]
+	return masker, ok
+}
+
+// MaskRulesWith returns the rules to mask the selectors with the masker.
+func MaskRulesWith(masker Masker, selectors ...string) []Rule {
+	rules := MaskRules(selectors...)
+
+	for i := range rules {
+		rules[i].Masker = masker
+	}
+
+	return rules
+}
+
+// WithMaskers adds the rules to mask each selector with its Masker.
+func WithMaskers(selectors map[string]Masker) RedactorOption {
+	return func(r *Redactor) {
+		for selector, masker := range selectors {
+			r.extraRules = append(r.extraRules, MaskRulesWith(masker, selector)...)
+		}
+	}
+}
+
+// maskString masks any value, formatting the non-strings as numbers and booleans.
+func maskString(masker Masker, value interface{}) string {
+	if masker == nil {
+		masker = GenericMasker
+	}
+
+	switch v := value.(type) {
+	case string:
+		return masker.Mask(v)
+	case []byte:
+		return masker.Mask(string(v))
+	default:
+		return masker.Mask(fmt.Sprint(v))
+	}
+}
+
+// maskDigits replaces the digits of value by *, except the first keepStart and last keepEnd digits.
+func maskDigits(value string, keepStart int, keepEnd int) string {
+	total := len(digits(value))
+	if total <= keepStart+keepEnd {
+		return maskValue(value)
+	}
+
+	return maskDigitRange(value, keepStart, total-keepEnd)
+}
+
+func maskCPF(value string) string {
+	if len(digits(value)) != 11 {
+		return maskValue(value)
+	}
+
+	return maskDigitRange(maskDigitRange(value, 9, 11), 0, 3)
+}
+
+func maskCNPJ(value string) string {
+	if len(digits(value)) != 14 {
+		return maskValue(value)
+	}
+
+	return maskDigitRange(value, 2, 8)
+}
+
+func maskPAN(value string) string {
+	return maskDigits(value, 6, 4)
+}
+
+func maskEmail(value string) string {
+	user, domain, found := strings.Cut(value, "@")
+	if !found || user == "" {
+		return maskValue(value)
+	}
+
+	runes := []rune(user)
+
+	return string(runes[0]) + strings.Repeat("*", len(runes)-1) + "@" + domain
+}
+
+func maskPhone(value string) string {
+	numbers := digits(value)
+
+	keepStart := 2
+	if len(numbers) >= 12 && numbers[0] == 5 && numbers[1] == 5 {
+		keepStart = 4
+	}
+
+	return maskDigits(value, keepStart, 4)
+}
+
+// maskDigitRange replaces the digits from the start to the end position by *.
+func maskDigitRange(value string, start int, end int) string {
+	result := []rune(value)
+	position := 0
+
+	for i, c := range result {
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		if position >= start && position < end {
+			result[i] = '*'
+		}
+
+		position++
+	}
+
+	return string(result)
+}
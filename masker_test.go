@@ -0,0 +1,109 @@
+package liberlogger
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMaskers(t *testing.T) {
+	tests := []struct {
+		name   string
+		masker Masker
+		value  string
+		want   string
+	}{
+		{name: "Should mask a formatted CPF", masker: CPFMasker, value: "529.982.247-25", want: "***.982.247-**"},
+		{name: "Should mask a CPF", masker: CPFMasker, value: "52998224725", want: "***982247**"},
+		{name: "Should mask a formatted CNPJ", masker: CNPJMasker, value: "11.222.333/0001-81", want: "11.***.***/0001-81"},
+		{name: "Should mask a card number", masker: PANMasker, value: "5111786674841746", want: "511178******1746"},
+		{name: "Should mask a card number with spaces", masker: PANMasker, value: "5111 7866 7484 1746", want: "5111 78** **** 1746"},
+		{name: "Should mask an email", masker: EmailMasker, value: "john.doe@liber.com", want: "j*******@liber.com"},
+		{name: "Should mask a phone", masker: PhoneMasker, value: "(11) 98765-4321", want: "(11) *****-4321"},
+		{name: "Should mask a phone with the country code", masker: PhoneMasker, value: "+55 11 98765-4321", want: "+55 11 *****-4321"},
+		{name: "Should mask the middle third", masker: GenericMasker, value: "123456", want: "12**56"},
+		{name: "Should fall back to the generic mask", masker: CPFMasker, value: "123", want: "1*3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.masker.Mask(tt.value); got != tt.want {
+				t.Errorf("Mask() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactor_maskers(t *testing.T) {
+	cpf, _ := LookupMasker("cpf")
+
+	tests := []struct {
+		name  string
+		rules []Rule
+		opts  []RedactorOption
+		body  interface{}
+		want  interface{}
+	}{
+		{
+			name:  "Should mask with the masker of the rule",
+			rules: MaskRulesWith(cpf, "document"),
+			body:  map[string]interface{}{"document": "529.982.247-25"},
+			want:  map[string]interface{}{"document": "***.982.247-**"},
+		},
+		{
+			name: "Should mask with the maskers of the keys",
+			opts: []RedactorOption{WithMaskers(map[string]Masker{"card.number": PANMasker, "email": EmailMasker})},
+			body: map[string]interface{}{
+				"card":  map[string]interface{}{"number": "5111786674841746"},
+				"email": "john.doe@liber.com",
+			},
+			want: map[string]interface{}{
+				"card":  map[string]interface{}{"number": "511178******1746"},
+				"email": "j*******@liber.com",
+			},
+		},
+		{
+			name:  "Should mask numbers and booleans",
+			rules: MaskRules("amount", "active"),
+			body:  map[string]interface{}{"amount": 123456, "active": true},
+			want:  map[string]interface{}{"amount": "12**56", "active": "tr**"},
+		},
+		{
+			name:  "Should mask every value of a nested object",
+			rules: MaskRules("payer"),
+			body: map[string]interface{}{
+				"payer": map[string]interface{}{"name": "John Doe", "ids": []interface{}{123456}},
+				"value": "100",
+			},
+			want: map[string]interface{}{
+				"payer": map[string]interface{}{"name": "Joh***oe", "ids": []interface{}{"12**56"}},
+				"value": "100",
+			},
+		},
+		{
+			name:  "Should redact inside a masked object",
+			rules: append(MaskRules("payer"), RedactRules("payer.token")...),
+			body:  map[string]interface{}{"payer": map[string]interface{}{"name": "John Doe", "token": "abc"}},
+			want:  map[string]interface{}{"payer": map[string]interface{}{"name": "Joh***oe", "token": REDACTED}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redactor, err := NewRedactor(tt.rules, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := redactor.Redact(tt.body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Redact() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedact_maskNonString(t *testing.T) {
+	body := map[string]interface{}{"document": 52998224725, "card": map[string]interface{}{"number": 5111786674841746}}
+	want := map[string]interface{}{"document": "5299****725", "card": map[string]interface{}{"number": "511178******1746"}}
+
+	if got := Redact([]string{}, []string{"document", "card"}, body); !reflect.DeepEqual(got, want) {
+		t.Errorf("Redact() = %+v, want %+v", got, want)
+	}
+}
@@ -1,6 +1,8 @@
 package liberlogger
 
 import (
+	"bytes"
+	"encoding/json"
 	"reflect"
 	"testing"
 )
@@ -252,6 +254,23 @@ func TestRedact(t *testing.T) {
 			},
 			want: map[string]interface{}{"Inner": map[string]interface{}{}, "Value": "REDACTED"},
 		},
+		{
+			name: "Should redact the keys with dots and brackets as they are, case-insensitively",
+			args: args{
+				keysToRedact: []string{"a.b", "items[0]"},
+				keysToMask:   []string{"User.Email"},
+				body:         map[string]interface{}{"a.b": "secret", "Items[0]": "first", "user.email": "joao@liber.com"},
+			},
+			want: map[string]interface{}{"a.b": "REDACTED", "Items[0]": "REDACTED", "user.email": "joao@*****.com"},
+		},
+		{
+			name: "Should redact the path selected by a key with dots",
+			args: args{
+				keysToRedact: []string{"a.b"},
+				body:         map[string]interface{}{"a": map[string]interface{}{"b": "secret", "c": "kept"}},
+			},
+			want: map[string]interface{}{"a": map[string]interface{}{"b": "REDACTED", "c": "kept"}},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -262,6 +281,42 @@ func TestRedact(t *testing.T) {
 	}
 }
 
+func TestRedact_rawJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		body interface{}
+		want interface{}
+	}{
+		{
+			name: "Should return the object of a bytes.Buffer unmarshaled",
+			body: *bytes.NewBufferString(`{"password": "123", "name": "Joao"}`),
+			want: map[string]interface{}{"password": "REDACTED", "name": "Joao"},
+		},
+		{
+			name: "Should return the array of a bytes.Buffer unmarshaled",
+			body: *bytes.NewBufferString(`[{"password": "123"}]`),
+			want: []interface{}{map[string]interface{}{"password": "REDACTED"}},
+		},
+		{
+			name: "Should return nil for an invalid bytes.Buffer",
+			body: *bytes.NewBufferString(`{"password":`),
+			want: nil,
+		},
+		{
+			name: "Should return a json.RawMessage as json.RawMessage",
+			body: json.RawMessage(`{"password": "123", "name": "Joao"}`),
+			want: json.RawMessage(`{"password":"REDACTED","name":"Joao"}`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Redact([]string{"password"}, []string{}, tt.body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Redact() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_maskValue(t *testing.T) {
 	type args struct {
 		value string
@@ -0,0 +1,473 @@
+package liberlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Action int
+
+const (
+	ActionRedact   Action = iota // Replaces the value with REDACTED.
+	ActionMask                   // Hides part of the value.
+	ActionTokenize               // Replaces the value with its keyed token, see SetTokenKey.
+)
+
+// Rule selects the fields to redact or mask. The Selector is either a plain key name, matched at any depth
+// and case-insensitively as the keys of Redact, or a dotted path from the root of the body:
+//
+//	payer.document        the document of the payer only
+//	items[*].card.number  the card number of every item
+//	items[0].id           the id of the first item
+//	*.token               the token of any object of the root
+//	**.token              the token at any depth, same as the plain key "token"
+type Rule struct {
+	Selector string
+	Action   Action
+	Masker   Masker // Masks the value of an ActionMask rule. Defaults to GenericMasker.
+}
+
+// RedactRules returns the rules to redact the selectors.
+func RedactRules(selectors ...string) []Rule {
+	return newRules(ActionRedact, selectors)
+}
+
+// MaskRules returns the rules to mask the selectors.
+func MaskRules(selectors ...string) []Rule {
+	return newRules(ActionMask, selectors)
+}
+
+func newRules(action Action, selectors []string) []Rule {
+	rules := make([]Rule, 0, len(selectors))
+
+	for _, selector := range selectors {
+		rules = append(rules, Rule{Selector: selector, Action: action})
+	}
+
+	return rules
+}
+
+// Redactor applies compiled rules to the bodies, so the selectors are parsed once and not on every log.
+type Redactor struct {
+	rules         []compiledRule
+	extraRules    []Rule
+	detectors     []Detector
+	pathTemplates []pathTemplate
+}
+
+type compiledRule struct {
+	segments []segment
+	action   Action
+	masker   Masker
+}
+
+type segmentKind int
+
+const (
+	segmentKey segmentKind = iota
+	segmentAnyKey
+	segmentIndex
+	segmentAnyIndex
+	segmentDeep
+)
+
+type segment struct {
+	kind  segmentKind
+	key   string
+	index int
+}
+
+type pathElem struct {
+	key     string
+	goName  string // Go name of a struct field, also matched by the selectors when not the key.
+	index   int
+	isIndex bool
+}
+
+// NewRedactor compiles the rules, returning an error for an invalid selector.
+func NewRedactor(rules []Rule, opts ...RedactorOption) (*Redactor, error) {
+	redactor := &Redactor{}
+
+	for _, opt := range opts {
+		opt(redactor)
+	}
+
+	for _, rule := range append(rules, redactor.extraRules...) {
+		segments, err := parseSelector(rule.Selector)
+		if err != nil {
+			return nil, err
+		}
+
+		redactor.rules = append(redactor.rules, compiledRule{segments: segments, action: rule.Action, masker: rule.Masker})
+	}
+
+	redactor.extraRules = nil
+
+	redactor.sortRules()
+
+	return redactor, nil
+}
+
+var keysRedactors sync.Map
+
+// keysRedactor returns the cached Redactor of the redact and mask keys. The keys match the fields of the
+// same name at any depth, as "user.email" does the key "user.email", and the paths they select when they
+// are valid selectors too.
+func keysRedactor(keysToRedact []string, keysToMask []string) *Redactor {
+	cacheKey := strings.Join(keysToRedact, "\x00") + "\x01" + strings.Join(keysToMask, "\x00")
+
+	if redactor, ok := keysRedactors.Load(cacheKey); ok {
+		return redactor.(*Redactor)
+	}
+
+	redactor := &Redactor{}
+
+	for _, rule := range append(RedactRules(keysToRedact...), MaskRules(keysToMask...)...) {
+		literal := []segment{{kind: segmentDeep}, {kind: segmentKey, key: rule.Selector}}
+		redactor.rules = append(redactor.rules, compiledRule{segments: literal, action: rule.Action})
+
+		if !strings.ContainsAny(rule.Selector, ".[*") {
+			continue
+		}
+
+		if segments, err := parseSelector(rule.Selector); err == nil {
+			redactor.rules = append(redactor.rules, compiledRule{segments: segments, action: rule.Action})
+		}
+	}
+
+	redactor.sortRules()
+
+	keysRedactors.Store(cacheKey, redactor)
+
+	return redactor
+}
+
+// sortRules keeps the redact rules first, so they win over the mask rules selecting the same field.
+func (r *Redactor) sortRules() {
+	redactRules := []compiledRule{}
+	otherRules := []compiledRule{}
+
+	for _, rule := range r.rules {
+		if rule.action == ActionRedact {
+			redactRules = append(redactRules, rule)
+		} else {
+			otherRules = append(otherRules, rule)
+		}
+	}
+
+	r.rules = append(redactRules, otherRules...)
+}
+
+func parseSelector(selector string) ([]segment, error) {
+	selector = strings.TrimPrefix(strings.TrimPrefix(selector, "$"), ".")
+
+	if selector == "" {
+		return nil, errors.New("empty selector")
+	}
+
+	if !strings.ContainsAny(selector, ".[*") {
+		return []segment{{kind: segmentDeep}, {kind: segmentKey, key: selector}}, nil
+	}
+
+	segments := []segment{}
+
+	for _, part := range strings.Split(selector, ".") {
+		switch part {
+		case "":
+			return nil, fmt.Errorf("invalid selector %q: empty segment", selector)
+		case "*":
+			segments = append(segments, segment{kind: segmentAnyKey})
+			continue
+		case "**":
+			segments = append(segments, segment{kind: segmentDeep})
+			continue
+		}
+
+		name, indexes, found := strings.Cut(part, "[")
+		if name != "" {
+			segments = append(segments, segment{kind: segmentKey, key: name})
+		}
+
+		if !found {
+			continue
+		}
+
+		for _, index := range strings.Split("["+indexes, "[")[1:] {
+			index, found := strings.CutSuffix(index, "]")
+			if !found {
+				return nil, fmt.Errorf("invalid selector %q: unclosed index", selector)
+			}
+
+			if index == "*" {
+				segments = append(segments, segment{kind: segmentAnyIndex})
+				continue
+			}
+
+			n, err := strconv.Atoi(index)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid selector %q: invalid index %q", selector, index)
+			}
+
+			segments = append(segments, segment{kind: segmentIndex, index: n})
+		}
+	}
+
+	return segments, nil
+}
+
+func (s segment) matches(elem pathElem) bool {
+	switch s.kind {
+	case segmentKey:
+		return !elem.isIndex && (strings.EqualFold(s.key, elem.key) || elem.goName != "" && strings.EqualFold(s.key, elem.goName))
+	case segmentIndex:
+		return elem.isIndex && s.index == elem.index
+	case segmentAnyIndex:
+		return elem.isIndex
+	default:
+		return true
+	}
+}
+
+func matchSegments(segments []segment, path []pathElem) bool {
+	if len(segments) == 0 {
+		return len(path) == 0
+	}
+
+	if segments[0].kind == segmentDeep {
+		for i := 0; i <= len(path); i++ {
+			if matchSegments(segments[1:], path[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(path) == 0 || !segments[0].matches(path[0]) {
+		return false
+	}
+
+	return matchSegments(segments[1:], path[1:])
+}
+
+// match returns the first rule selecting the path. When bypassed, only the keys always redacted are.
+func (r *Redactor) match(path []pathElem, bypass bool) (compiledRule, bool) {
+	if bypass && alwaysRedacted(path) {
+		return compiledRule{action: ActionRedact}, true
+	}
+
+	for _, rule := range r.rules {
+		if bypass && rule.action == ActionRedact {
+			continue
+		}
+
+		if matchSegments(rule.segments, path) {
+			return rule, true
+		}
+	}
+
+	return compiledRule{}, false
+}
+
+// Redact returns a copy of body with the fields selected by the rules redacted or masked. Structs, maps
+// and the JSON of a bytes.Buffer are returned as map[string]interface{} or []interface{}, while a
+// json.RawMessage is redacted by RedactJSON and returned as json.RawMessage.
+func (r *Redactor) Redact(body interface{}) interface{} {
+	if body == nil {
+		return nil
+	}
+
+	switch bodyParse := body.(type) {
+	case bytes.Buffer:
+		var parsed interface{}
+		if err := json.Unmarshal(bodyParse.Bytes(), &parsed); err != nil {
+			return nil
+		}
+
+		return r.Redact(parsed)
+	case json.RawMessage:
+		return r.redactRawJSON(bodyParse)
+	case truncatedBody:
+		return r.redactTruncated(bodyParse)
+	case string:
+		return map[string]interface{}{
+			"plain/text-type": r.detect(bodyParse, ignoreRedacted()),
+		}
+	case []interface{}:
+		newBody := []interface{}{}
+		w := walker{redactor: r, bypass: ignoreRedacted()}
+
+		for i, value := range bodyParse {
+			if value == nil {
+				newBody = append(newBody, nil)
+				continue
+			}
+
+			if _, ok := value.(string); ok {
+				newBody = append(newBody, r.Redact(value))
+				continue
+			}
+
+			newBody = append(newBody, w.walk(value, []pathElem{{index: i, isIndex: true}}))
+		}
+
+		return newBody
+	default:
+		w := walker{redactor: r, bypass: ignoreRedacted()}
+		return w.walk(body, nil)
+	}
+}
+
+type walker struct {
+	redactor  *Redactor
+	bypass    bool
+	inherited *compiledRule // Set while walking an object or array selected by a mask or tokenize rule.
+}
+
+func (w *walker) walk(input interface{}, path []pathElem) interface{} {
+	inputValue := reflect.ValueOf(input)
+	inputType := reflect.TypeOf(input)
+	newBody := map[string]interface{}{}
+
+	switch inputType.Kind() {
+	case reflect.Map:
+		redacted, restore := w.container(path)
+		if redacted {
+			return REDACTED
+		}
+		defer restore()
+
+		for _, index := range inputValue.MapKeys() {
+			value := inputValue.MapIndex(index)
+			fieldName := index.String()
+			if !value.IsValid() {
+				continue
+			}
+
+			if value.Interface() == nil {
+				continue
+			}
+
+			newBody[fieldName] = w.walk(value.Interface(), append(path, pathElem{key: fieldName}))
+		}
+	case reflect.Struct:
+		redacted, restore := w.container(path)
+		if redacted {
+			return REDACTED
+		}
+		defer restore()
+
+		for _, field := range structFields(inputType) {
+			value, err := inputValue.FieldByIndexErr(field.index)
+			if err != nil || !value.IsValid() || !value.CanInterface() {
+				continue
+			}
+
+			if value.Interface() == nil || (field.omitEmpty && value.IsZero()) {
+				continue
+			}
+
+			fieldPath := append(path, pathElem{key: field.name, goName: field.goName})
+
+			if field.rule != nil && !(w.bypass && field.rule.action == ActionRedact) {
+				newBody[field.name] = w.tagged(field.rule, value.Interface(), fieldPath)
+				continue
+			}
+
+			newBody[field.name] = w.walk(value.Interface(), fieldPath)
+		}
+	case reflect.Ptr:
+		if !inputValue.IsNil() {
+			return w.walk(inputValue.Elem().Interface(), path)
+		}
+	case reflect.Slice, reflect.Array:
+		if inputType.Elem().Kind() == reflect.Uint8 {
+			return w.leaf(input, path)
+		}
+
+		redacted, restore := w.container(path)
+		if redacted {
+			return REDACTED
+		}
+		defer restore()
+
+		newSlice := make([]interface{}, 0, inputValue.Len())
+
+		for i := 0; i < inputValue.Len(); i++ {
+			value := inputValue.Index(i)
+
+			if value.Kind() == reflect.Interface && value.IsNil() {
+				newSlice = append(newSlice, nil)
+				continue
+			}
+
+			newSlice = append(newSlice, w.walk(value.Interface(), append(path, pathElem{index: i, isIndex: true})))
+		}
+
+		return newSlice
+	default:
+		return w.leaf(input, path)
+	}
+
+	return newBody
+}
+
+// container checks the rules selecting an object or array. It returns true when redacted, otherwise a
+// mask or tokenize rule applies to its values until the returned restore is called.
+func (w *walker) container(path []pathElem) (bool, func()) {
+	if len(path) == 0 {
+		return false, func() {}
+	}
+
+	rule, ok := w.redactor.match(path, w.bypass)
+	if !ok {
+		return false, func() {}
+	}
+
+	if rule.action == ActionRedact {
+		return true, func() {}
+	}
+
+	previous := w.inherited
+	w.inherited = &rule
+
+	return false, func() { w.inherited = previous }
+}
+
+func (w *walker) leaf(value interface{}, path []pathElem) interface{} {
+	if len(path) == 0 || value == nil {
+		return value
+	}
+
+	rule, ok := w.redactor.match(path, w.bypass)
+	if !ok {
+		if w.inherited != nil {
+			return w.inherited.apply(value)
+		}
+
+		if str, isString := value.(string); isString {
+			return w.redactor.detect(str, w.bypass)
+		}
+
+		return value
+	}
+
+	return rule.apply(value)
+}
+
+func (r compiledRule) apply(value interface{}) string {
+	switch r.action {
+	case ActionMask:
+		return maskString(r.masker, value)
+	case ActionTokenize:
+		return tokenizeValue(value)
+	default:
+		return REDACTED
+	}
+}
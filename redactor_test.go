@@ -0,0 +1,121 @@
+package liberlogger
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedactor_Redact(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []Rule
+		body  interface{}
+		want  interface{}
+	}{
+		{
+			name:  "Should redact a plain key at any depth",
+			rules: RedactRules("document"),
+			body: map[string]interface{}{
+				"document": "58707647000",
+				"payer":    map[string]interface{}{"Document": "58707647000"},
+			},
+			want: map[string]interface{}{
+				"document": REDACTED,
+				"payer":    map[string]interface{}{"Document": REDACTED},
+			},
+		},
+		{
+			name:  "Should redact only the selected path",
+			rules: RedactRules("payer.document"),
+			body: map[string]interface{}{
+				"payer":   map[string]interface{}{"document": "58707647000"},
+				"company": map[string]interface{}{"document": "11222333000181"},
+			},
+			want: map[string]interface{}{
+				"payer":   map[string]interface{}{"document": REDACTED},
+				"company": map[string]interface{}{"document": "11222333000181"},
+			},
+		},
+		{
+			name:  "Should redact every item of an array",
+			rules: RedactRules("items[*].card.number"),
+			body: map[string]interface{}{
+				"items": []interface{}{
+					map[string]interface{}{"card": map[string]interface{}{"number": "5111786674841746", "brand": "master"}},
+					map[string]interface{}{"card": map[string]interface{}{"number": "4111111111111111", "brand": "visa"}},
+				},
+			},
+			want: map[string]interface{}{
+				"items": []interface{}{
+					map[string]interface{}{"card": map[string]interface{}{"number": REDACTED, "brand": "master"}},
+					map[string]interface{}{"card": map[string]interface{}{"number": REDACTED, "brand": "visa"}},
+				},
+			},
+		},
+		{
+			name:  "Should redact only the selected index of an array",
+			rules: RedactRules("items[1].id"),
+			body:  map[string]interface{}{"items": []map[string]string{{"id": "1"}, {"id": "2"}}},
+			want: map[string]interface{}{"items": []interface{}{
+				map[string]interface{}{"id": "1"},
+				map[string]interface{}{"id": REDACTED},
+			}},
+		},
+		{
+			name:  "Should redact with a deep wildcard",
+			rules: RedactRules("**.auth.token"),
+			body: map[string]interface{}{
+				"token": "root",
+				"a":     map[string]interface{}{"b": map[string]interface{}{"auth": map[string]interface{}{"token": "deep"}}},
+			},
+			want: map[string]interface{}{
+				"token": "root",
+				"a":     map[string]interface{}{"b": map[string]interface{}{"auth": map[string]interface{}{"token": REDACTED}}},
+			},
+		},
+		{
+			name:  "Should redact a whole object",
+			rules: RedactRules("card"),
+			body:  map[string]interface{}{"card": map[string]interface{}{"number": "5111786674841746"}},
+			want:  map[string]interface{}{"card": REDACTED},
+		},
+		{
+			name:  "Should prefer redact over mask",
+			rules: append(MaskRules("document"), RedactRules("payer.document")...),
+			body: map[string]interface{}{
+				"payer":    map[string]interface{}{"document": "58707647000"},
+				"document": "58707647000",
+			},
+			want: map[string]interface{}{
+				"payer":    map[string]interface{}{"document": REDACTED},
+				"document": "5870****000",
+			},
+		},
+		{
+			name:  "Should redact the items of a root array",
+			rules: RedactRules("[*].id"),
+			body:  []interface{}{map[string]interface{}{"id": "1", "name": "a"}},
+			want:  []interface{}{map[string]interface{}{"id": REDACTED, "name": "a"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redactor, err := NewRedactor(tt.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := redactor.Redact(tt.body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Redact() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRedactor_invalidSelector(t *testing.T) {
+	for _, selector := range []string{"", "payer..document", "items[*.id", "items[a].id", "items[-1]"} {
+		if _, err := NewRedactor(RedactRules(selector)); err == nil {
+			t.Errorf("NewRedactor(%q) error = nil, want an error", selector)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package liberlogger
+
+import (
+	"fmt"
+	"math/rand"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// RouteMatch is how the Pattern of a RouteRule matches the path of the requests.
+type RouteMatch int
+
+const (
+	// MatchExact matches the path equal to the pattern, ignoring the trailing slash.
+	MatchExact RouteMatch = iota
+	// MatchPrefix matches the pattern and the paths under it, so /metrics matches /metrics/cpu but not /metricsx.
+	MatchPrefix
+	// MatchGlob matches the pattern as path.Match, where * does not cross a /.
+	MatchGlob
+	// MatchRegex matches the regular expression of the pattern anywhere in the path, unless anchored.
+	MatchRegex
+)
+
+// RouteAction is how the requests of a RouteRule are logged.
+type RouteAction int
+
+const (
+	// RouteLog logs the requests with the bodies, as the requests not matched. It is the zero value, for
+	// the rules only overriding the Levels or the SlowThreshold.
+	RouteLog RouteAction = iota
+	// RouteSkip does not log the requests.
+	RouteSkip
+	// RouteHeadersOnly logs the headers and the extra fields, without reading the bodies.
+	RouteHeadersOnly
+	// RouteNoBody logs the content type and the size of the bodies instead of their content.
+	RouteNoBody
+	// RouteSample logs, with the bodies, the Sample percent of the requests.
+	RouteSample
+)
+
+// RouteRule selects the requests by path and method and sets how they are logged.
+type RouteRule struct {
+	Pattern string
+	Match   RouteMatch
+	Methods []string // Methods matched, any when empty.
+	Action  RouteAction
+	Sample  int // Percent of the requests logged by RouteSample, from 0 to 100.
+	// Levels and SlowThreshold override the ones of the middleware when set.
+	Levels        LevelMapper
+	SlowThreshold time.Duration
+}
+
+// RouteRules are the compiled RouteRule, the first matching a request applies.
+type RouteRules struct {
+	rules []compiledRouteRule
+}
+
+type compiledRouteRule struct {
+	RouteRule
+	regex *regexp.Regexp
+}
+
+// NewRouteRules compiles the rules, failing on an invalid glob or regular expression.
+func NewRouteRules(rules ...RouteRule) (*RouteRules, error) {
+	compiled := make([]compiledRouteRule, 0, len(rules))
+
+	for _, rule := range rules {
+		c := compiledRouteRule{RouteRule: rule}
+
+		switch rule.Match {
+		case MatchExact, MatchPrefix:
+			c.Pattern = trimSlash(rule.Pattern)
+		case MatchGlob:
+			if _, err := path.Match(rule.Pattern, ""); err != nil {
+				return nil, fmt.Errorf("liberlogger: invalid route glob %q: %w", rule.Pattern, err)
+			}
+		case MatchRegex:
+			regex, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("liberlogger: invalid route regex %q: %w", rule.Pattern, err)
+			}
+
+			c.regex = regex
+		default:
+			return nil, fmt.Errorf("liberlogger: invalid route match %d", rule.Match)
+		}
+
+		if rule.Sample < 0 || rule.Sample > 100 {
+			return nil, fmt.Errorf("liberlogger: invalid route sample %d, want from 0 to 100", rule.Sample)
+		}
+
+		compiled = append(compiled, c)
+	}
+
+	return &RouteRules{rules: compiled}, nil
+}
+
+// ignoreRouteRules are the RouteSkip rules of the exact routes.
+func ignoreRouteRules(routes []string) []compiledRouteRule {
+	rules := make([]compiledRouteRule, 0, len(routes))
+
+	for _, route := range routes {
+		rules = append(rules, compiledRouteRule{RouteRule: RouteRule{Pattern: trimSlash(route), Action: RouteSkip}})
+	}
+
+	return rules
+}
+
+// match returns the first rule matching the request.
+func (rr *RouteRules) match(r *http.Request) (RouteRule, bool) {
+	if rr == nil {
+		return RouteRule{}, false
+	}
+
+	for _, rule := range rr.rules {
+		if rule.matches(r) {
+			return rule.RouteRule, true
+		}
+	}
+
+	return RouteRule{}, false
+}
+
+func (rule compiledRouteRule) matches(r *http.Request) bool {
+	if len(rule.Methods) > 0 && !containsFold(rule.Methods, r.Method) {
+		return false
+	}
+
+	urlPath := r.URL.Path
+
+	switch rule.Match {
+	case MatchExact:
+		return trimSlash(urlPath) == rule.Pattern
+	case MatchPrefix:
+		urlPath = trimSlash(urlPath)
+		return urlPath == rule.Pattern || strings.HasPrefix(urlPath, strings.TrimSuffix(rule.Pattern, "/")+"/")
+	case MatchGlob:
+		matched, _ := path.Match(rule.Pattern, urlPath)
+		if !matched && urlPath != "/" {
+			matched, _ = path.Match(rule.Pattern, strings.TrimSuffix(urlPath, "/"))
+		}
+
+		return matched
+	case MatchRegex:
+		return rule.regex.MatchString(urlPath)
+	}
+
+	return false
+}
+
+// sampled reports whether a request of a RouteSample rule is logged.
+func (rule RouteRule) sampled() bool {
+	return rand.Intn(100) < rule.Sample
+}
+
+// trimSlash removes the trailing slashes of the path, keeping the root.
+func trimSlash(urlPath string) string {
+	if trimmed := strings.TrimRight(urlPath, "/"); trimmed != "" {
+		return trimmed
+	}
+
+	return "/"
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bodyMetadata is the body of the RouteNoBody logs.
+func bodyMetadata(header http.Header, size int64) interface{} {
+	if size <= 0 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+
+	return map[string]interface{}{"content_type": mediaType, "size": size}
+}
@@ -0,0 +1,210 @@
+package liberlogger
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRouteRulesMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   RouteRule
+		method string
+		target string
+		want   bool
+	}{
+		{
+			name:   "Should match the exact path ignoring the query",
+			rule:   RouteRule{Pattern: "/health"},
+			target: "/health?full=1",
+			want:   true,
+		},
+		{
+			name:   "Should match the exact path ignoring the trailing slash",
+			rule:   RouteRule{Pattern: "/health"},
+			target: "/health/",
+			want:   true,
+		},
+		{
+			name:   "Should not match other paths of the exact path",
+			rule:   RouteRule{Pattern: "/health"},
+			target: "/healthz",
+		},
+		{
+			name:   "Should match the paths under the prefix",
+			rule:   RouteRule{Pattern: "/metrics", Match: MatchPrefix},
+			target: "/metrics/cpu/usage",
+			want:   true,
+		},
+		{
+			name:   "Should not match the paths only starting with the prefix",
+			rule:   RouteRule{Pattern: "/metrics", Match: MatchPrefix},
+			target: "/metricsx",
+		},
+		{
+			name:   "Should match the glob",
+			rule:   RouteRule{Pattern: "/users/*/documents", Match: MatchGlob},
+			target: "/users/1/documents/",
+			want:   true,
+		},
+		{
+			name:   "Should not match the glob across the slash",
+			rule:   RouteRule{Pattern: "/metrics/*", Match: MatchGlob},
+			target: "/metrics/cpu/usage",
+		},
+		{
+			name:   "Should match the regex",
+			rule:   RouteRule{Pattern: `^/v[0-9]+/status$`, Match: MatchRegex},
+			target: "/v2/status",
+			want:   true,
+		},
+		{
+			name:   "Should match the method",
+			rule:   RouteRule{Pattern: "/status", Methods: []string{"get"}},
+			method: http.MethodGet,
+			target: "/status",
+			want:   true,
+		},
+		{
+			name:   "Should not match other methods",
+			rule:   RouteRule{Pattern: "/status", Methods: []string{http.MethodGet}},
+			method: http.MethodPost,
+			target: "/status",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := NewRouteRules(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			if _, got := rules.match(httptest.NewRequest(method, tt.target, nil)); got != tt.want {
+				t.Errorf("match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRouteRulesInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		rule RouteRule
+	}{
+		{
+			name: "Should fail on an invalid glob",
+			rule: RouteRule{Pattern: "/users/[", Match: MatchGlob},
+		},
+		{
+			name: "Should fail on an invalid regex",
+			rule: RouteRule{Pattern: "/users/(", Match: MatchRegex},
+		},
+		{
+			name: "Should fail on a sample over 100",
+			rule: RouteRule{Pattern: "/users", Action: RouteSample, Sample: 101},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewRouteRules(tt.rule); err == nil {
+				t.Error("NewRouteRules() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestHTTPMiddlewareRouteActions(t *testing.T) {
+	tests := []struct {
+		name         string
+		rule         RouteRule
+		wantLogs     int
+		wantBody     bool
+		wantRespBody interface{}
+	}{
+		{
+			name:         "Should log the route of the rule without an action",
+			rule:         RouteRule{Pattern: "/users", Levels: StatusLevel},
+			wantLogs:     2,
+			wantBody:     true,
+			wantRespBody: map[string]interface{}{"id": "1"},
+		},
+		{
+			name:     "Should not log the skipped route",
+			rule:     RouteRule{Pattern: "/users", Action: RouteSkip},
+			wantLogs: 0,
+		},
+		{
+			name:     "Should log the headers without the bodies",
+			rule:     RouteRule{Pattern: "/users", Action: RouteHeadersOnly},
+			wantLogs: 2,
+		},
+		{
+			name:         "Should log the content type and size of the bodies",
+			rule:         RouteRule{Pattern: "/users", Action: RouteNoBody},
+			wantLogs:     2,
+			wantBody:     true,
+			wantRespBody: map[string]interface{}{"content_type": "application/json", "size": float64(len(`{"id":"1"}`))},
+		},
+		{
+			name:         "Should log every request with a sample of 100",
+			rule:         RouteRule{Pattern: "/users", Action: RouteSample, Sample: 100},
+			wantLogs:     2,
+			wantBody:     true,
+			wantRespBody: map[string]interface{}{"id": "1"},
+		},
+		{
+			name:     "Should not log the requests with a sample of 0",
+			rule:     RouteRule{Pattern: "/users", Action: RouteSample},
+			wantLogs: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			rules, err := NewRouteRules(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":"1"}`))
+			})
+
+			server := withLogger(New(WithOutput(&out)), HTTPMiddleware(MiddlewareRouteRules(rules))(handler))
+
+			req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"John"}`))
+			req.Header.Set("Content-Type", "application/json")
+			server.ServeHTTP(httptest.NewRecorder(), req)
+
+			logs := readLogs(t, &out)
+			if len(logs) != tt.wantLogs {
+				t.Fatalf("logs = %v, want %d logs", logs, tt.wantLogs)
+			}
+
+			for _, log := range logs {
+				if _, ok := log["headers"]; !ok {
+					t.Errorf("log = %v, want the headers", log)
+				}
+
+				if _, ok := log["body"]; ok != tt.wantBody {
+					t.Errorf("log = %v, want the body %v", log, tt.wantBody)
+				}
+			}
+
+			if tt.wantRespBody != nil && !reflect.DeepEqual(logs[1]["body"], tt.wantRespBody) {
+				t.Errorf("response body = %v, want %v", logs[1]["body"], tt.wantRespBody)
+			}
+		})
+	}
+}
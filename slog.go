@@ -0,0 +1,174 @@
+package liberlogger
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+)
+
+// SlogHandler is a slog.Handler writing through a Logger, with the context fields, the dd trace and span
+// ids and the redaction keys of the Logger applied to the attributes.
+type SlogHandler struct {
+	logger *Logger // nil means the Logger from the context of each record
+	groups []string
+	attrs  []groupedAttr
+}
+
+type groupedAttr struct {
+	groups []string
+	attr   slog.Attr
+}
+
+// NewSlogHandler returns a slog.Handler writing through the Logger of the context passed to
+// slog.InfoContext and the others, or the Default one.
+func NewSlogHandler() *SlogHandler {
+	return &SlogHandler{}
+}
+
+// SlogHandler returns a slog.Handler writing through the Logger.
+func (l *Logger) SlogHandler() *SlogHandler {
+	return &SlogHandler{logger: l}
+}
+
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return slogLevel(level) >= h.loggerFrom(ctx).Level()
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	logger := h.loggerFrom(ctx)
+
+	event := logger.newEvent(ctx, slogLevel(record.Level))
+	if event == nil {
+		return nil
+	}
+
+	if span, ok := tracer.SpanFromContext(ctx); ok {
+		if _, ok := FieldsFromContext(ctx)["dd.trace_id"]; !ok {
+			event.
+				Uint64("dd.span_id", span.Context().SpanID()).
+				Uint64("dd.trace_id", span.Context().TraceID())
+		}
+	}
+
+	attrs := map[string]interface{}{}
+
+	for _, grouped := range h.attrs {
+		addSlogAttr(attrs, grouped.groups, grouped.attr)
+	}
+
+	record.Attrs(func(attr slog.Attr) bool {
+		addSlogAttr(attrs, h.groups, attr)
+		return true
+	})
+
+	if len(attrs) > 0 {
+		event.Fields(logger.Redact(attrs))
+	}
+
+	event.Msg(record.Message)
+
+	return nil
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handler := *h
+	handler.attrs = make([]groupedAttr, 0, len(h.attrs)+len(attrs))
+	handler.attrs = append(handler.attrs, h.attrs...)
+
+	for _, attr := range attrs {
+		handler.attrs = append(handler.attrs, groupedAttr{groups: h.groups, attr: attr})
+	}
+
+	return &handler
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	handler := *h
+	handler.groups = append(append(make([]string, 0, len(h.groups)+1), h.groups...), name)
+
+	return &handler
+}
+
+func (h *SlogHandler) loggerFrom(ctx context.Context) *Logger {
+	if h.logger != nil {
+		return h.logger
+	}
+
+	if ctx == nil {
+		return Default()
+	}
+
+	return FromContext(ctx)
+}
+
+func addSlogAttr(fields map[string]interface{}, groups []string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		groupAttrs := attr.Value.Group()
+		if len(groupAttrs) == 0 {
+			return
+		}
+
+		if attr.Key != "" {
+			groups = append(append(make([]string, 0, len(groups)+1), groups...), attr.Key)
+		}
+
+		for _, groupAttr := range groupAttrs {
+			addSlogAttr(fields, groups, groupAttr)
+		}
+
+		return
+	}
+
+	for _, group := range groups {
+		nested, ok := fields[group].(map[string]interface{})
+		if !ok {
+			nested = map[string]interface{}{}
+			fields[group] = nested
+		}
+
+		fields = nested
+	}
+
+	fields[attr.Key] = slogValue(attr.Value)
+}
+
+func slogValue(value slog.Value) interface{} {
+	switch value.Kind() {
+	case slog.KindTime:
+		return value.Time().Format(time.RFC3339Nano)
+	case slog.KindDuration:
+		return value.Duration().String()
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			return err.Error()
+		}
+	}
+
+	return value.Any()
+}
+
+func slogLevel(level slog.Level) zerolog.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return zerolog.DebugLevel
+	case level < slog.LevelWarn:
+		return zerolog.InfoLevel
+	case level < slog.LevelError:
+		return zerolog.WarnLevel
+	default:
+		return zerolog.ErrorLevel
+	}
+}
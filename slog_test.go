@@ -0,0 +1,84 @@
+package liberlogger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestSlogHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(ctx context.Context, logger *slog.Logger)
+		want map[string]interface{}
+	}{
+		{
+			name: "Should log the attributes with the context fields",
+			log: func(ctx context.Context, logger *slog.Logger) {
+				logger.InfoContext(ctx, "info msg", "user", "joao")
+			},
+			want: map[string]interface{}{"level": "info", "message": "info msg", "log_id": "123", "user": "joao"},
+		},
+		{
+			name: "Should redact and mask the attributes",
+			log: func(ctx context.Context, logger *slog.Logger) {
+				logger.WarnContext(ctx, "warn msg", "password", "secret", "document", "58707647000")
+			},
+			want: map[string]interface{}{"level": "warn", "message": "warn msg", "log_id": "123", "password": "REDACTED", "document": "5870****000"},
+		},
+		{
+			name: "Should nest the attributes of groups",
+			log: func(ctx context.Context, logger *slog.Logger) {
+				logger.With("partner", "bank").WithGroup("request").ErrorContext(ctx, "error msg", "password", "secret", slog.Group("card", "code", 123))
+			},
+			want: map[string]interface{}{
+				"level":   "error",
+				"message": "error msg",
+				"log_id":  "123",
+				"partner": "bank",
+				"request": map[string]interface{}{
+					"password": "REDACTED",
+					"card":     map[string]interface{}{"code": float64(123)},
+				},
+			},
+		},
+		{
+			name: "Should not log below the level of the Logger",
+			log: func(ctx context.Context, logger *slog.Logger) {
+				logger.DebugContext(ctx, "debug msg")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			logger := New(WithOutput(&out), WithRedactKeys("password"), WithMaskKeys("document"))
+
+			ctx := WithField(context.Background(), "log_id", "123")
+
+			tt.log(ctx, slog.New(logger.SlogHandler()))
+
+			if tt.want == nil {
+				if out.Len() > 0 {
+					t.Errorf("log = %s, want nothing", out.String())
+				}
+				return
+			}
+
+			var got map[string]interface{}
+			if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+				t.Fatalf("invalid log %q: %v", out.String(), err)
+			}
+
+			delete(got, "time")
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("log = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
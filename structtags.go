@@ -0,0 +1,118 @@
+package liberlogger
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// structField is a field of a struct as logged: named by its json tag and with the rule of its log tag. The
+// selectors match the json name and the Go name of the field.
+//
+//	Document string `json:"document" log:"mask=cpf"`
+//	Token    string `json:"token" log:"redact"`
+//	Customer string `json:"customer" log:"tokenize"`
+//	Internal string `log:"-"`
+type structField struct {
+	index     []int
+	name      string
+	goName    string
+	omitEmpty bool
+	rule      *compiledRule
+}
+
+var structFieldsCache sync.Map
+
+// structFields returns the logged fields of the struct type, with the embedded structs flattened as
+// encoding/json does.
+func structFields(structType reflect.Type) []structField {
+	if fields, ok := structFieldsCache.Load(structType); ok {
+		return fields.([]structField)
+	}
+
+	fields := parseStructFields(structType, nil)
+
+	structFieldsCache.Store(structType, fields)
+
+	return fields
+}
+
+func parseStructFields(structType reflect.Type, index []int) []structField {
+	fields := []structField{}
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+
+		logTag := field.Tag.Get("log")
+		if logTag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && options == "" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				fields = append(fields, parseStructFields(embedded, fieldIndex)...)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, structField{
+			index:     fieldIndex,
+			name:      name,
+			goName:    field.Name,
+			omitEmpty: strings.Contains(","+options+",", ",omitempty,"),
+			rule:      parseLogTag(logTag),
+		})
+	}
+
+	return fields
+}
+
+// parseLogTag returns the rule of the log tag: redact, mask, mask=<masker name> or tokenize.
+func parseLogTag(tag string) *compiledRule {
+	action, maskerName, _ := strings.Cut(tag, "=")
+
+	switch strings.TrimSpace(action) {
+	case "redact":
+		return &compiledRule{action: ActionRedact}
+	case "mask":
+		masker, _ := LookupMasker(strings.TrimSpace(maskerName))
+		return &compiledRule{action: ActionMask, masker: masker}
+	case "tokenize":
+		return &compiledRule{action: ActionTokenize}
+	default:
+		return nil
+	}
+}
+
+// tagged walks a value with the rule of its log tag. The rules of the Redactor selecting the value inside
+// still apply.
+func (w *walker) tagged(rule *compiledRule, value interface{}, path []pathElem) interface{} {
+	if rule.action == ActionRedact {
+		return REDACTED
+	}
+
+	previous := w.inherited
+	w.inherited = rule
+	defer func() { w.inherited = previous }()
+
+	return w.walk(value, path)
+}
@@ -0,0 +1,129 @@
+package liberlogger
+
+import (
+	"reflect"
+	"testing"
+)
+
+type taggedAddress struct {
+	Street string `json:"street"`
+	Number int    `json:"number" log:"mask"`
+}
+
+type taggedAudit struct {
+	CreatedBy string `json:"created_by"`
+}
+
+type taggedPayer struct {
+	taggedAudit
+	Name     string         `json:"name"`
+	Document string         `json:"document" log:"mask=cpf"`
+	Password string         `json:"password" log:"redact"`
+	Customer string         `json:"customer" log:"tokenize"`
+	Internal string         `json:"internal" log:"-"`
+	Ignored  string         `json:"-"`
+	Nickname string         `json:"nickname,omitempty"`
+	Address  *taggedAddress `json:"address"`
+	Card     *Card          `json:"card,omitempty" log:"redact"`
+	Go       string
+	secret   string
+}
+
+func TestRedactor_structTags(t *testing.T) {
+	payer := taggedPayer{
+		taggedAudit: taggedAudit{CreatedBy: "admin"},
+		Name:        "Joao Silva",
+		Document:    "529.982.247-25",
+		Password:    "123456",
+		Customer:    "42",
+		Internal:    "internal",
+		Ignored:     "ignored",
+		Address:     &taggedAddress{Street: "Av. Paulista", Number: 1000},
+		Card:        &Card{Code: 123, Brand: "Master"},
+		Go:          "go",
+		secret:      "secret",
+	}
+
+	tests := []struct {
+		name  string
+		rules []Rule
+		want  interface{}
+	}{
+		{
+			name: "Should follow the json and log tags",
+			want: map[string]interface{}{
+				"created_by": "admin",
+				"name":       "Joao Silva",
+				"document":   "***.982.247-**",
+				"password":   REDACTED,
+				"customer":   REDACTED,
+				"address":    map[string]interface{}{"street": "Av. Paulista", "number": "10**"},
+				"card":       REDACTED,
+				"Go":         "go",
+			},
+		},
+		{
+			name:  "Should apply the rules to the json names",
+			rules: RedactRules("address.street", "created_by"),
+			want: map[string]interface{}{
+				"created_by": REDACTED,
+				"name":       "Joao Silva",
+				"document":   "***.982.247-**",
+				"password":   REDACTED,
+				"customer":   REDACTED,
+				"address":    map[string]interface{}{"street": REDACTED, "number": "10**"},
+				"card":       REDACTED,
+				"Go":         "go",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redactor, err := NewRedactor(tt.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := redactor.Redact(payer); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Redact() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedact_structGoNames(t *testing.T) {
+	type account struct {
+		DocumentNumber string `json:"document_number"`
+		AccessToken    string `json:"access_token"`
+		BankCode       string `json:"bank_code"`
+	}
+
+	body := account{DocumentNumber: "12345678909", AccessToken: "abc", BankCode: "341"}
+
+	tests := []struct {
+		name         string
+		keysToRedact []string
+		keysToMask   []string
+		want         map[string]interface{}
+	}{
+		{
+			name:         "Should redact the fields listed by their Go names",
+			keysToRedact: []string{"AccessToken"},
+			keysToMask:   []string{"DocumentNumber"},
+			want:         map[string]interface{}{"document_number": "1234****909", "access_token": REDACTED, "bank_code": "341"},
+		},
+		{
+			name:         "Should redact the fields listed by their json names",
+			keysToRedact: []string{"access_token"},
+			keysToMask:   []string{"document_number"},
+			want:         map[string]interface{}{"document_number": "1234****909", "access_token": REDACTED, "bank_code": "341"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Redact(tt.keysToRedact, tt.keysToMask, body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Redact() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
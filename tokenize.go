@@ -0,0 +1,55 @@
+package liberlogger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync/atomic"
+)
+
+// TokenPrefix starts the tokens of ActionTokenize.
+const TokenPrefix = "tok_"
+
+var tokenKey atomic.Pointer[[]byte]
+
+// SetTokenKey sets the secret key of the ActionTokenize tokens, so the same value always maps to the same
+// token where the key is shared. Without a key the tokenized values are replaced by REDACTED.
+func SetTokenKey(key []byte) {
+	if len(key) == 0 {
+		tokenKey.Store(nil)
+		return
+	}
+
+	key = append([]byte{}, key...)
+	tokenKey.Store(&key)
+}
+
+// TokenizeRules returns the rules to tokenize the selectors.
+func TokenizeRules(selectors ...string) []Rule {
+	return newRules(ActionTokenize, selectors)
+}
+
+// Tokenize returns the keyed HMAC-SHA256 token of the value, as tok_ followed by 32 hex characters.
+func Tokenize(value string) string {
+	key := tokenKey.Load()
+	if key == nil {
+		return REDACTED
+	}
+
+	mac := hmac.New(sha256.New, *key)
+	mac.Write([]byte(value))
+
+	return TokenPrefix + hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+func tokenizeValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return Tokenize(v)
+	case []byte:
+		return Tokenize(string(v))
+	default:
+		return Tokenize(fmt.Sprint(v))
+	}
+}
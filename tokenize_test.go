@@ -0,0 +1,63 @@
+package liberlogger
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	SetTokenKey(nil)
+
+	if got := Tokenize("52998224725"); got != REDACTED {
+		t.Errorf("Tokenize() without key = %v, want %v", got, REDACTED)
+	}
+
+	SetTokenKey([]byte("secret"))
+	defer SetTokenKey(nil)
+
+	token := Tokenize("52998224725")
+
+	if !strings.HasPrefix(token, TokenPrefix) || len(token) != len(TokenPrefix)+32 {
+		t.Errorf("Tokenize() = %v, want a %s token", token, TokenPrefix)
+	}
+
+	if got := Tokenize("52998224725"); got != token {
+		t.Errorf("Tokenize() = %v, want the same token %v", got, token)
+	}
+
+	if got := Tokenize("11222333000181"); got == token {
+		t.Errorf("Tokenize() of another value = %v, want another token", got)
+	}
+
+	SetTokenKey([]byte("other"))
+
+	if got := Tokenize("52998224725"); got == token {
+		t.Errorf("Tokenize() with another key = %v, want another token", got)
+	}
+}
+
+func TestRedactor_tokenize(t *testing.T) {
+	SetTokenKey([]byte("secret"))
+	defer SetTokenKey(nil)
+
+	redactor, err := NewRedactor(TokenizeRules("document", "payer"), WithDetectors(CPFDetector(ActionTokenize)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := map[string]interface{}{
+		"document":    "52998224725",
+		"payer":       map[string]interface{}{"id": 10},
+		"description": "customer 52998224725",
+	}
+	want := map[string]interface{}{
+		"document":    Tokenize("52998224725"),
+		"payer":       map[string]interface{}{"id": Tokenize("10")},
+		"description": "customer " + Tokenize("52998224725"),
+	}
+
+	if got := redactor.Redact(body); !reflect.DeepEqual(got, want) {
+		t.Errorf("Redact() = %+v, want %+v", got, want)
+	}
+}
@@ -79,7 +79,7 @@ func StartContextAndSpan(ctx context.Context, traceConfig SpanConfig) (context.C
 		"log_id":      uuid.NewString(),
 	}
 
-	return context.WithValue(ctx, liberlogger.LogFieldsKey{}, logFields), span
+	return liberlogger.WithFields(ctx, logFields), span
 }
 
 func SpanFromContext(ctx context.Context) (ddtrace.Span, bool) {
@@ -103,7 +103,7 @@ func StartSpanFromContext(ctx context.Context, traceConfig SpanConfig) (ddtrace.
 		"log_id":      uuid.NewString(),
 	}
 
-	return span, context.WithValue(ctx, liberlogger.LogFieldsKey{}, logFields)
+	return span, liberlogger.WithFields(ctx, logFields)
 }
 
 func AddTraceAndSpanToLog(ctx context.Context) context.Context {
@@ -114,7 +114,7 @@ func AddTraceAndSpanToLog(ctx context.Context) context.Context {
 			"log_id":      uuid.NewString(),
 		}
 
-		ctx = context.WithValue(ctx, liberlogger.LogFieldsKey{}, logFields)
+		ctx = liberlogger.WithFields(ctx, logFields)
 	}
 
 	return ctx
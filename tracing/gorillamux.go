@@ -1,7 +1,6 @@
 package tracing
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -40,7 +39,7 @@ func GorillaMuxTrace(traceConfig GorillaMuxConfig) *muxtrace.Router {
 					"log_id":      uuid.NewString(),
 				}
 
-				r = r.WithContext(context.WithValue(ctx, liberlogger.LogFieldsKey{}, logFields))
+				r = r.WithContext(liberlogger.WithFields(ctx, logFields))
 			}
 
 			next.ServeHTTP(w, r)
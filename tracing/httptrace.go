@@ -1,7 +1,6 @@
 package tracing
 
 import (
-	"context"
 	"math"
 	"net/http"
 
@@ -33,7 +32,7 @@ func HttpTrace(
 				"log_id":      uuid.NewString(),
 			}
 
-			r = r.WithContext(context.WithValue(r.Context(), liberlogger.LogFieldsKey{}, logFields))
+			r = r.WithContext(liberlogger.WithFields(r.Context(), logFields))
 		}),
 	}
 
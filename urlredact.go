@@ -0,0 +1,146 @@
+package liberlogger
+
+import (
+	"net/url"
+	"strings"
+)
+
+// pathTemplate is a path with placeholders, as /customers/{cpf}, whose segments are masked in the URLs.
+type pathTemplate struct {
+	segments []string
+}
+
+// WithPathTemplates masks the path segments of the URLs matching the templates, as /customers/{cpf}. The
+// placeholder name is the key of the segment: a rule selecting it applies, otherwise it is masked with the
+// Masker of the same name, as cpf, or the GenericMasker.
+func WithPathTemplates(templates ...string) RedactorOption {
+	return func(r *Redactor) {
+		for _, template := range templates {
+			r.pathTemplates = append(r.pathTemplates, pathTemplate{segments: strings.Split(template, "/")})
+		}
+	}
+}
+
+// RedactURL returns the URL without the userinfo, with the query parameters selected by the rules and the
+// path segments of the templates redacted or masked.
+func (r *Redactor) RedactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+
+	redacted := *u
+	redacted.User = nil
+	bypass := ignoreRedacted()
+
+	if path, ok := r.redactPath(u.Path, bypass); ok {
+		redacted.Path = path
+		redacted.RawPath = escapeRedacted(path, url.PathEscape, "/")
+	}
+
+	if u.RawQuery != "" {
+		redacted.RawQuery = r.redactQuery(u.RawQuery, bypass)
+	}
+
+	return redacted.String()
+}
+
+func (r *Redactor) redactPath(path string, bypass bool) (string, bool) {
+	segments := strings.Split(path, "/")
+
+	for _, template := range r.pathTemplates {
+		if len(template.segments) != len(segments) {
+			continue
+		}
+
+		matched := true
+
+		for i, segment := range template.segments {
+			if !isPlaceholder(segment) && segment != segments[i] {
+				matched = false
+				break
+			}
+		}
+
+		if !matched {
+			continue
+		}
+
+		redacted := make([]string, len(segments))
+
+		for i, segment := range template.segments {
+			redacted[i] = segments[i]
+
+			if isPlaceholder(segment) {
+				redacted[i] = r.redactPathSegment(segment[1:len(segment)-1], segments[i], bypass)
+			}
+		}
+
+		return strings.Join(redacted, "/"), true
+	}
+
+	return path, false
+}
+
+func (r *Redactor) redactPathSegment(name string, value string, bypass bool) string {
+	if rule, ok := r.match([]pathElem{{key: name}}, bypass); ok {
+		return rule.apply(value)
+	}
+
+	masker, ok := LookupMasker(name)
+	if !ok {
+		masker = GenericMasker
+	}
+
+	return masker.Mask(value)
+}
+
+// redactQuery redacts the values of the query parameters selected by the rules, keeping their order.
+func (r *Redactor) redactQuery(rawQuery string, bypass bool) string {
+	pairs := strings.Split(rawQuery, "&")
+
+	for i, pair := range pairs {
+		rawKey, rawValue, _ := strings.Cut(pair, "=")
+
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			continue
+		}
+
+		value, err := url.QueryUnescape(rawValue)
+		if err != nil {
+			continue
+		}
+
+		redacted := value
+		if rule, ok := r.match([]pathElem{{key: key}}, bypass); ok {
+			redacted = rule.apply(value)
+		} else {
+			redacted = r.detect(value, bypass)
+		}
+
+		if redacted != value {
+			pairs[i] = rawKey + "=" + escapeRedacted(redacted, url.QueryEscape, "")
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+func isPlaceholder(segment string) bool {
+	return len(segment) > 2 && strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// escapeRedacted escapes the value keeping the * of the masks readable. A separator splits the value in
+// parts escaped one by one.
+func escapeRedacted(value string, escape func(string) string, separator string) string {
+	parts := []string{value}
+	if separator != "" {
+		parts = strings.Split(value, separator)
+	}
+
+	for i, part := range parts {
+		parts[i] = strings.ReplaceAll(escape(part), "%2A", "*")
+	}
+
+	return strings.Join(parts, separator)
+}